@@ -17,10 +17,20 @@ const (
 	BearerAuthScopes = "bearerAuth.Scopes"
 )
 
+// Defines values for CommentStatus.
+const (
+	Approved CommentStatus = "approved"
+	Pending  CommentStatus = "pending"
+	Rejected CommentStatus = "rejected"
+	Spam     CommentStatus = "spam"
+)
+
 // Defines values for GetArticlesParamsOrderby.
 const (
-	CreatedAt GetArticlesParamsOrderby = "created_at"
-	ViewCount GetArticlesParamsOrderby = "view_count"
+	CommentCount GetArticlesParamsOrderby = "comment_count"
+	CreatedAt    GetArticlesParamsOrderby = "created_at"
+	LikeCount    GetArticlesParamsOrderby = "like_count"
+	ViewCount    GetArticlesParamsOrderby = "view_count"
 )
 
 // Defines values for GetArticlesParamsOrder.
@@ -29,6 +39,32 @@ const (
 	Desc GetArticlesParamsOrder = "desc"
 )
 
+// Defines values for GetArticlesTrendingParamsWindow.
+const (
+	N24h GetArticlesTrendingParamsWindow = "24h"
+	N30d GetArticlesTrendingParamsWindow = "30d"
+	N7d  GetArticlesTrendingParamsWindow = "7d"
+)
+
+// Defines values for GetCommentsParamsFormat.
+const (
+	Flat GetCommentsParamsFormat = "flat"
+	Tree GetCommentsParamsFormat = "tree"
+)
+
+// Defines values for GetCommentsParamsSort.
+const (
+	Newest    GetCommentsParamsSort = "newest"
+	Oldest    GetCommentsParamsSort = "oldest"
+	Reactions GetCommentsParamsSort = "reactions"
+)
+
+// Defines values for ReactionTarget.
+const (
+	ReactionTargetArticle ReactionTarget = "article"
+	ReactionTargetComment ReactionTarget = "comment"
+)
+
 // ArchiveResponse defines model for ArchiveResponse.
 type ArchiveResponse struct {
 	Archive *map[string][]Article `json:"archive,omitempty"`
@@ -36,18 +72,45 @@ type ArchiveResponse struct {
 
 // Article defines model for Article.
 type Article struct {
-	Author    *string    `json:"author,omitempty"`
-	AuthorId  *string    `json:"author_id,omitempty"`
-	Category  *Category  `json:"category,omitempty"`
-	Content   *string    `json:"content,omitempty"`
-	CreatedAt *time.Time `json:"created_at,omitempty"`
-	Id        *string    `json:"id,omitempty"`
-	ImageUrl  *string    `json:"image_url,omitempty"`
-	LikeCount *int       `json:"like_count,omitempty"`
-	Tags      *[]Tag     `json:"tags,omitempty"`
-	Title     *string    `json:"title,omitempty"`
-	UpdatedAt *time.Time `json:"updated_at,omitempty"`
-	ViewCount *int       `json:"view_count,omitempty"`
+	// Alternates Other languages this article is available in (hreflang alternates)
+	Alternates *[]ArticleAlternate `json:"alternates,omitempty"`
+	Author     *string             `json:"author,omitempty"`
+	AuthorId   *string             `json:"author_id,omitempty"`
+	Category   *Category           `json:"category,omitempty"`
+
+	// CommentCount Number of comments on the article, excluding trashed ones
+	CommentCount *int       `json:"comment_count,omitempty"`
+	Content      *string    `json:"content,omitempty"`
+	CreatedAt    *time.Time `json:"created_at,omitempty"`
+	Excerpt      *string    `json:"excerpt,omitempty"`
+	Id           *string    `json:"id,omitempty"`
+	ImageUrl     *string    `json:"image_url,omitempty"`
+
+	// Lang Language of the returned title and content
+	Lang      *string `json:"lang,omitempty"`
+	LikeCount *int    `json:"like_count,omitempty"`
+
+	// Liked True if the current visitor has liked the article
+	Liked *bool `json:"liked,omitempty"`
+
+	// Reactions Count of each allowed emoji reaction, flagging the ones the current visitor added
+	Reactions *[]ReactionSummary `json:"reactions,omitempty"`
+	Series    *ArticleSeries     `json:"series,omitempty"`
+	Slug      *string            `json:"slug,omitempty"`
+	Tags      *[]Tag             `json:"tags,omitempty"`
+	Title     *string            `json:"title,omitempty"`
+	UpdatedAt *time.Time         `json:"updated_at,omitempty"`
+
+	// Version Edit version of the article. Also returned as the ETag of GET /articles/{id}
+	Version   *int `json:"version,omitempty"`
+	ViewCount *int `json:"view_count,omitempty"`
+}
+
+// ArticleAlternate defines model for ArticleAlternate.
+type ArticleAlternate struct {
+	Href     *string `json:"href,omitempty"`
+	Hreflang *string `json:"hreflang,omitempty"`
+	Slug     *string `json:"slug,omitempty"`
 }
 
 // ArticleByAuthor defines model for ArticleByAuthor.
@@ -69,6 +132,54 @@ type ArticleResponse struct {
 	} `json:"condition,omitempty"`
 }
 
+// ArticleSeries Series membership of an article
+type ArticleSeries struct {
+	Id            *string `json:"id,omitempty"`
+	NextArticleId *string `json:"next_article_id,omitempty"`
+
+	// Part 1-based position of the article in the series
+	Part          *int    `json:"part,omitempty"`
+	PrevArticleId *string `json:"prev_article_id,omitempty"`
+	Title         *string `json:"title,omitempty"`
+
+	// Total Number of articles in the series
+	Total *int `json:"total,omitempty"`
+}
+
+// ArticleTagsRequest defines model for ArticleTagsRequest.
+type ArticleTagsRequest struct {
+	// Names Tag names. Tags that do not exist yet are created
+	Names *[]string `json:"names,omitempty"`
+
+	// TagIds IDs of existing tags
+	TagIds *[]string `json:"tagIds,omitempty"`
+}
+
+// ArticleTranslation defines model for ArticleTranslation.
+type ArticleTranslation struct {
+	Content   *string    `json:"content,omitempty"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+	Excerpt   *string    `json:"excerpt,omitempty"`
+	Lang      *string    `json:"lang,omitempty"`
+	Slug      *string    `json:"slug,omitempty"`
+	Title     *string    `json:"title,omitempty"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+// CacheStats defines model for CacheStats.
+type CacheStats struct {
+	// Capacity Maximum number of cached entries
+	Capacity  *int   `json:"capacity,omitempty"`
+	Enabled   *bool  `json:"enabled,omitempty"`
+	Evictions *int64 `json:"evictions,omitempty"`
+	Hits      *int64 `json:"hits,omitempty"`
+	Misses    *int64 `json:"misses,omitempty"`
+
+	// Size Number of cached entries
+	Size       *int `json:"size,omitempty"`
+	TtlSeconds *int `json:"ttl_seconds,omitempty"`
+}
+
 // Category defines model for Category.
 type Category struct {
 	Id   *string `json:"id,omitempty"`
@@ -77,18 +188,112 @@ type Category struct {
 
 // Comment defines model for Comment.
 type Comment struct {
-	ArticleId *string    `json:"articleId,omitempty"`
-	Author    *string    `json:"author,omitempty"`
-	Content   *string    `json:"content,omitempty"`
+	ArticleId *string `json:"articleId,omitempty"`
+	Author    *string `json:"author,omitempty"`
+
+	// Content Markdown source of the comment
+	Content *string `json:"content,omitempty"`
+
+	// ContentHtml Sanitized HTML rendered from the Markdown subset (code blocks, inline code, links with rel="nofollow ugc", emphasis). Images and raw HTML are shown as text.
+	ContentHtml *string `json:"contentHtml,omitempty"`
+
+	// CreatedAt When the comment was originally posted (edits do not change it)
 	CreatedAt *time.Time `json:"created_at,omitempty"`
+
+	// Deleted True if the comment was deleted and is kept only as a placeholder for its replies
+	Deleted *bool `json:"deleted,omitempty"`
+
+	// Depth Nesting depth (0 for top-level comments)
+	Depth *int `json:"depth,omitempty"`
+
+	// Edited True if the comment was edited after posting
+	Edited *bool `json:"edited,omitempty"`
+
+	// EditedAt When the comment was last edited
+	EditedAt *time.Time `json:"editedAt,omitempty"`
+	Id       *string    `json:"id,omitempty"`
+
+	// ModerationReason Why the comment was held for moderation. Only returned to administrators with status.
+	ModerationReason *string `json:"moderationReason,omitempty"`
+
+	// ParentId ID of the comment this replies to (absent for top-level comments)
+	ParentId *string `json:"parentId,omitempty"`
+
+	// ReactionCount Number of reactions to this comment
+	ReactionCount *int `json:"reactionCount,omitempty"`
+
+	// Reactions Count of each allowed emoji reaction, flagging the ones the current visitor added
+	Reactions *[]ReactionSummary `json:"reactions,omitempty"`
+
+	// Replies Replies to this comment (only with format=tree)
+	Replies *[]Comment `json:"replies,omitempty"`
+
+	// ReplyCount Number of replies under this comment, at all depths
+	ReplyCount *int `json:"replyCount,omitempty"`
+
+	// SpamScore Spam score from 0 to 1 calculated when the comment was posted. Only returned to administrators with status.
+	SpamScore *float64       `json:"spamScore,omitempty"`
+	Status    *CommentStatus `json:"status,omitempty"`
+}
+
+// CommentListResponse defines model for CommentListResponse.
+type CommentListResponse struct {
+	Comments *[]Comment `json:"comments,omitempty"`
+	Page     *int       `json:"page,omitempty"`
+	PerPage  *int       `json:"perPage,omitempty"`
+
+	// Threads Number of top-level threads, across all pages
+	Threads *int `json:"threads,omitempty"`
+
+	// Total Number of visible comments on the article, across all pages
+	Total *int `json:"total,omitempty"`
+}
+
+// CommentRevision defines model for CommentRevision.
+type CommentRevision struct {
+	CommentId *string `json:"commentId,omitempty"`
+
+	// Content Content of the comment before the edit
+	Content *string `json:"content,omitempty"`
+
+	// EditedBy ID of the user who made the edit
+	EditedBy *string `json:"editedBy,omitempty"`
+	Id       *string `json:"id,omitempty"`
+
+	// ReplacedAt When this version was replaced by an edit
+	ReplacedAt *time.Time `json:"replacedAt,omitempty"`
+}
+
+// CommentStatus defines model for CommentStatus.
+type CommentStatus string
+
+// ContactMessage defines model for ContactMessage.
+type ContactMessage struct {
+	CreatedAt *time.Time `json:"createdAt,omitempty"`
+	Email     *string    `json:"email,omitempty"`
 	Id        *string    `json:"id,omitempty"`
+	IsSpam    *bool      `json:"isSpam,omitempty"`
+	Message   *string    `json:"message,omitempty"`
+	Name      *string    `json:"name,omitempty"`
+
+	// SpamReasons Why the message scored as spam
+	SpamReasons *string `json:"spamReasons,omitempty"`
+
+	// SpamScore Spam score from 0 to 1
+	SpamScore *float64 `json:"spamScore,omitempty"`
 }
 
 // ContactRequest defines model for ContactRequest.
 type ContactRequest struct {
-	Email   *openapi_types.Email `json:"email,omitempty"`
-	Message *string              `json:"message,omitempty"`
-	Name    *string              `json:"name,omitempty"`
+	Email *openapi_types.Email `json:"email,omitempty"`
+
+	// FormRenderedAt When the form was shown to the user, used to detect submissions that are too fast
+	FormRenderedAt *time.Time `json:"formRenderedAt,omitempty"`
+	Message        *string    `json:"message,omitempty"`
+	Name           *string    `json:"name,omitempty"`
+
+	// Website Honeypot field. Keep it hidden and empty; submissions that fill it are treated as spam
+	Website *string `json:"website,omitempty"`
 }
 
 // ContactResponse defines model for ContactResponse.
@@ -119,8 +324,7 @@ type ImageUploadResponse struct {
 
 // LikeRequest defines model for LikeRequest.
 type LikeRequest struct {
-	ArticleId string  `json:"articleId"`
-	UserId    *string `json:"userId,omitempty"`
+	ArticleId string `json:"articleId"`
 }
 
 // LikeReturn defines model for LikeReturn.
@@ -131,29 +335,89 @@ type LikeReturn struct {
 	UserId    *string `json:"userId,omitempty"`
 }
 
+// LikeStateRequest defines model for LikeStateRequest.
+type LikeStateRequest struct {
+	ArticleId string `json:"articleId"`
+
+	// Liked Whether the visitor should like the article after the request
+	Liked bool `json:"liked"`
+}
+
 // LoginRequest defines model for LoginRequest.
 type LoginRequest struct {
 	Email    openapi_types.Email `json:"email"`
 	Password string              `json:"password"`
 }
 
+// LoginResponse defines model for LoginResponse.
+type LoginResponse struct {
+	Email     *openapi_types.Email `json:"email,omitempty"`
+	ExpiresAt time.Time            `json:"expiresAt"`
+	Id        string               `json:"id"`
+	Token     string               `json:"token"`
+	Username  *string              `json:"username,omitempty"`
+}
+
+// ModerationRequest defines model for ModerationRequest.
+type ModerationRequest struct {
+	Ids []string `json:"ids"`
+
+	// Spam Mark the comments as spam instead of rejected (reject only)
+	Spam *bool `json:"spam,omitempty"`
+}
+
+// ModerationResult defines model for ModerationResult.
+type ModerationResult struct {
+	Updated *int `json:"updated,omitempty"`
+}
+
 // NewArticle defines model for NewArticle.
 type NewArticle struct {
-	Author   string    `json:"author"`
-	AuthorId *string   `json:"author_id,omitempty"`
-	Category string    `json:"category"`
-	Content  string    `json:"content"`
-	IsAdmin  bool      `json:"is_admin"`
-	Tags     *[]string `json:"tags,omitempty"`
-	Title    string    `json:"title"`
+	Author   string  `json:"author"`
+	AuthorId *string `json:"author_id,omitempty"`
+	Category string  `json:"category"`
+	Content  string  `json:"content"`
+	IsAdmin  bool    `json:"is_admin"`
+
+	// Lang Language of the article (defaults to the default language)
+	Lang  *string   `json:"lang,omitempty"`
+	Tags  *[]string `json:"tags,omitempty"`
+	Title string    `json:"title"`
 }
 
 // NewComment defines model for NewComment.
 type NewComment struct {
-	ArticleId string  `json:"articleId"`
-	Content   string  `json:"content"`
-	UserId    *string `json:"userId,omitempty"`
-	Username  string  `json:"username"`
+	ArticleId string `json:"articleId"`
+
+	// Content Comment text in the Markdown subset allowed for comments
+	Content string `json:"content"`
+
+	// Email Email address for reply notifications. Never shown with the comment
+	Email *openapi_types.Email `json:"email,omitempty"`
+
+	// FormRenderedAt When the comment form was shown to the user, used to detect submissions that are too fast
+	FormRenderedAt *time.Time `json:"formRenderedAt,omitempty"`
+
+	// NotifyReplies Email replies to this comment to the given address. Each email includes a one-click unsubscribe link
+	NotifyReplies *bool `json:"notifyReplies,omitempty"`
+
+	// ParentId ID of the comment to reply to
+	ParentId *string `json:"parentId,omitempty"`
+
+	// UserId Ignored. The author is the logged-in user (Bearer token) or the visitor with the same IP address.
+	// Deprecated: this property has been marked as deprecated upstream, but no `x-deprecated-reason` was set
+	UserId   *string `json:"userId,omitempty"`
+	Username string  `json:"username"`
+
+	// Website Honeypot field. Keep it hidden and empty; submissions that fill it are treated as spam
+	Website *string `json:"website,omitempty"`
+}
+
+// NewSeries defines model for NewSeries.
+type NewSeries struct {
+	ArticleIds  *[]string `json:"article_ids,omitempty"`
+	Description *string   `json:"description,omitempty"`
+	Title       string    `json:"title"`
 }
 
 // ProfileResponse defines model for ProfileResponse.
@@ -180,6 +444,33 @@ type ProfileResponse struct {
 // RSSFeed URL of the RSS feed
 type RSSFeed = string
 
+// ReactionRequest defines model for ReactionRequest.
+type ReactionRequest struct {
+	// Emoji One of the emoji allowed by REACTION_EMOJI
+	Emoji      string         `json:"emoji"`
+	TargetId   string         `json:"targetId"`
+	TargetType ReactionTarget `json:"targetType"`
+}
+
+// ReactionResponse defines model for ReactionResponse.
+type ReactionResponse struct {
+	Reactions  *[]ReactionSummary `json:"reactions,omitempty"`
+	TargetId   *string            `json:"targetId,omitempty"`
+	TargetType *ReactionTarget    `json:"targetType,omitempty"`
+}
+
+// ReactionSummary defines model for ReactionSummary.
+type ReactionSummary struct {
+	Count *int    `json:"count,omitempty"`
+	Emoji *string `json:"emoji,omitempty"`
+
+	// Reacted True if the current visitor added this reaction
+	Reacted *bool `json:"reacted,omitempty"`
+}
+
+// ReactionTarget defines model for ReactionTarget.
+type ReactionTarget string
+
 // RegisterUser defines model for RegisterUser.
 type RegisterUser struct {
 	Email    openapi_types.Email `json:"email"`
@@ -187,10 +478,32 @@ type RegisterUser struct {
 	Username string              `json:"username"`
 }
 
+// Series defines model for Series.
+type Series struct {
+	ArticleIds  *[]string  `json:"article_ids,omitempty"`
+	Articles    *[]Article `json:"articles,omitempty"`
+	CreatedAt   *time.Time `json:"created_at,omitempty"`
+	Description *string    `json:"description,omitempty"`
+	Id          *string    `json:"id,omitempty"`
+	Title       *string    `json:"title,omitempty"`
+	UpdatedAt   *time.Time `json:"updated_at,omitempty"`
+}
+
 // Tag defines model for Tag.
 type Tag struct {
-	Id   *string `json:"id,omitempty"`
-	Name *string `json:"name,omitempty"`
+	// ArticleCount Number of articles with this tag, excluding trashed ones. Only returned by GET /tags and tag administration
+	ArticleCount *int    `json:"article_count,omitempty"`
+	Id           *string `json:"id,omitempty"`
+	Name         *string `json:"name,omitempty"`
+}
+
+// TagMergeRequest defines model for TagMergeRequest.
+type TagMergeRequest struct {
+	// SourceIds Tags to merge and move to the trash
+	SourceIds []string `json:"sourceIds"`
+
+	// TargetId Tag to keep
+	TargetId string `json:"targetId"`
 }
 
 // TagRequest defines model for TagRequest.
@@ -199,13 +512,71 @@ type TagRequest struct {
 	Tag       Tag    `json:"tag"`
 }
 
+// TagUpdate defines model for TagUpdate.
+type TagUpdate struct {
+	Name string `json:"name"`
+}
+
+// TranslationRequest defines model for TranslationRequest.
+type TranslationRequest struct {
+	Content string  `json:"content"`
+	Excerpt *string `json:"excerpt,omitempty"`
+	Slug    string  `json:"slug"`
+	Title   string  `json:"title"`
+}
+
+// TrashItem defines model for TrashItem.
+type TrashItem struct {
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	Id        *string    `json:"id,omitempty"`
+
+	// Label Title of the article, content of the comment or name of the tag
+	Label *string `json:"label,omitempty"`
+
+	// PurgeAt Time after which the item is deleted permanently
+	PurgeAt *time.Time `json:"purge_at,omitempty"`
+}
+
+// TrashResponse defines model for TrashResponse.
+type TrashResponse struct {
+	Articles *[]TrashItem `json:"articles,omitempty"`
+	Comments *[]TrashItem `json:"comments,omitempty"`
+	Tags     *[]TrashItem `json:"tags,omitempty"`
+}
+
+// TrendingArticle defines model for TrendingArticle.
+type TrendingArticle struct {
+	Article *Article `json:"article,omitempty"`
+	Score   *float64 `json:"score,omitempty"`
+}
+
+// TrendingCategory defines model for TrendingCategory.
+type TrendingCategory struct {
+	Articles *[]TrendingArticle `json:"articles,omitempty"`
+	Category *Category          `json:"category,omitempty"`
+}
+
+// TrendingResponse defines model for TrendingResponse.
+type TrendingResponse struct {
+	Categories *[]TrendingCategory `json:"categories,omitempty"`
+
+	// ComputedAt When the scores were last refreshed (absent before the first refresh)
+	ComputedAt *time.Time         `json:"computedAt,omitempty"`
+	Overall    *[]TrendingArticle `json:"overall,omitempty"`
+	Window     *string            `json:"window,omitempty"`
+}
+
 // UpdateArticle defines model for UpdateArticle.
 type UpdateArticle struct {
-	AuthorId *string   `json:"author_id,omitempty"`
-	Category *string   `json:"category,omitempty"`
-	Content  *string   `json:"content,omitempty"`
-	Tags     *[]string `json:"tags,omitempty"`
-	Title    *string   `json:"title,omitempty"`
+	AuthorId *string `json:"author_id,omitempty"`
+
+	// Category ID of the category
+	Category *string `json:"category,omitempty"`
+	Content  *string `json:"content,omitempty"`
+
+	// Tags IDs of the tags. Replaces the current tags of the article
+	Tags  *[]string `json:"tags,omitempty"`
+	Title *string   `json:"title,omitempty"`
 }
 
 // UpdateComment defines model for UpdateComment.
@@ -213,6 +584,23 @@ type UpdateComment struct {
 	Content *string `json:"content,omitempty"`
 }
 
+// UpdateSeries defines model for UpdateSeries.
+type UpdateSeries struct {
+	ArticleIds  *[]string `json:"article_ids,omitempty"`
+	Description *string   `json:"description,omitempty"`
+	Title       *string   `json:"title,omitempty"`
+}
+
+// VersionConflict defines model for VersionConflict.
+type VersionConflict struct {
+	// Etag Current ETag of the article
+	Etag    *string `json:"etag,omitempty"`
+	Message *string `json:"message,omitempty"`
+
+	// Version Current version of the article
+	Version *int `json:"version,omitempty"`
+}
+
 // GetArticlesParams defines parameters for GetArticles.
 type GetArticlesParams struct {
 	// Page Page number for pagination
@@ -227,11 +615,14 @@ type GetArticlesParams struct {
 	// Search Search for articles by title or content
 	Search *string `form:"search,omitempty" json:"search,omitempty"`
 
-	// Orderby Sort articles by created_at or view count (default created_at)
+	// Orderby Sort articles by created_at, view count, like count or comment count (default created_at)
 	Orderby *GetArticlesParamsOrderby `form:"orderby,omitempty" json:"orderby,omitempty"`
 
 	// Order Sort articles by created_at or view count in ascending or descending order (default created_at desc)
 	Order *GetArticlesParamsOrder `form:"order,omitempty" json:"order,omitempty"`
+
+	// Lang Only return articles readable in this language, shown in that language
+	Lang *string `form:"lang,omitempty" json:"lang,omitempty"`
 }
 
 // GetArticlesParamsOrderby defines parameters for GetArticles.
@@ -240,9 +631,67 @@ type GetArticlesParamsOrderby string
 // GetArticlesParamsOrder defines parameters for GetArticles.
 type GetArticlesParamsOrder string
 
+// GetArticlesTrendingParams defines parameters for GetArticlesTrending.
+type GetArticlesTrendingParams struct {
+	// Window Period to rank over (default 7d)
+	Window *GetArticlesTrendingParamsWindow `form:"window,omitempty" json:"window,omitempty"`
+
+	// Limit Number of articles overall and per category (default 10, max 50)
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
+// GetArticlesTrendingParamsWindow defines parameters for GetArticlesTrending.
+type GetArticlesTrendingParamsWindow string
+
+// GetArticlesIdParams defines parameters for GetArticlesId.
+type GetArticlesIdParams struct {
+	// Lang Preferred language. Falls back to Accept-Language, then the default language.
+	Lang *string `form:"lang,omitempty" json:"lang,omitempty"`
+}
+
 // GetCommentsParams defines parameters for GetComments.
 type GetCommentsParams struct {
 	ArticleId string `form:"articleId" json:"articleId"`
+
+	// Format Return a flat list with depth and parentId (default), or a tree of top-level comments with nested replies
+	Format *GetCommentsParamsFormat `form:"format,omitempty" json:"format,omitempty"`
+
+	// Sort Order of top-level threads (default oldest)
+	Sort *GetCommentsParamsSort `form:"sort,omitempty" json:"sort,omitempty"`
+
+	// Page Page number, starting at 1
+	Page *int `form:"page,omitempty" json:"page,omitempty"`
+
+	// PerPage Top-level threads per page (default 20, max 100)
+	PerPage *int `form:"perPage,omitempty" json:"perPage,omitempty"`
+}
+
+// GetCommentsParamsFormat defines parameters for GetComments.
+type GetCommentsParamsFormat string
+
+// GetCommentsParamsSort defines parameters for GetComments.
+type GetCommentsParamsSort string
+
+// GetCommentsModerationParams defines parameters for GetCommentsModeration.
+type GetCommentsModerationParams struct {
+	// Status Moderation status to list (default pending)
+	Status *CommentStatus `form:"status,omitempty" json:"status,omitempty"`
+}
+
+// GetCommentsSubscriptionsIdUnsubscribeParams defines parameters for GetCommentsSubscriptionsIdUnsubscribe.
+type GetCommentsSubscriptionsIdUnsubscribeParams struct {
+	Token string `form:"token" json:"token"`
+}
+
+// GetContactMessagesParams defines parameters for GetContactMessages.
+type GetContactMessagesParams struct {
+	// Spam Only return messages judged as spam (true) or not (false)
+	Spam *bool `form:"spam,omitempty" json:"spam,omitempty"`
+}
+
+// DeleteLikesParams defines parameters for DeleteLikes.
+type DeleteLikesParams struct {
+	ArticleId string `form:"articleId" json:"articleId"`
 }
 
 // GetLikesParams defines parameters for GetLikes.
@@ -250,12 +699,43 @@ type GetLikesParams struct {
 	ArticleId string `form:"articleId" json:"articleId"`
 }
 
+// GetLikesStatusParams defines parameters for GetLikesStatus.
+type GetLikesStatusParams struct {
+	// ArticleIds Up to 100 article IDs
+	ArticleIds []string `form:"articleIds" json:"articleIds"`
+}
+
+// DeleteReactionsParams defines parameters for DeleteReactions.
+type DeleteReactionsParams struct {
+	TargetType ReactionTarget `form:"targetType" json:"targetType"`
+	TargetId   string         `form:"targetId" json:"targetId"`
+	Emoji      string         `form:"emoji" json:"emoji"`
+}
+
+// GetReactionsParams defines parameters for GetReactions.
+type GetReactionsParams struct {
+	TargetType ReactionTarget `form:"targetType" json:"targetType"`
+	TargetId   string         `form:"targetId" json:"targetId"`
+}
+
+// GetRssParams defines parameters for GetRss.
+type GetRssParams struct {
+	// Lang Return the feed of articles readable in this language
+	Lang *string `form:"lang,omitempty" json:"lang,omitempty"`
+}
+
 // PostArticlesJSONRequestBody defines body for PostArticles for application/json ContentType.
 type PostArticlesJSONRequestBody = NewArticle
 
 // PatchArticlesIdJSONRequestBody defines body for PatchArticlesId for application/json ContentType.
 type PatchArticlesIdJSONRequestBody = UpdateArticle
 
+// PutArticlesIdTagsJSONRequestBody defines body for PutArticlesIdTags for application/json ContentType.
+type PutArticlesIdTagsJSONRequestBody = ArticleTagsRequest
+
+// PutArticlesIdTranslationsLangJSONRequestBody defines body for PutArticlesIdTranslationsLang for application/json ContentType.
+type PutArticlesIdTranslationsLangJSONRequestBody = TranslationRequest
+
 // PostAuthLoginJSONRequestBody defines body for PostAuthLogin for application/json ContentType.
 type PostAuthLoginJSONRequestBody = LoginRequest
 
@@ -268,6 +748,12 @@ type PostCategoriesJSONRequestBody = Category
 // PostCommentsJSONRequestBody defines body for PostComments for application/json ContentType.
 type PostCommentsJSONRequestBody = NewComment
 
+// PostCommentsModerationApproveJSONRequestBody defines body for PostCommentsModerationApprove for application/json ContentType.
+type PostCommentsModerationApproveJSONRequestBody = ModerationRequest
+
+// PostCommentsModerationRejectJSONRequestBody defines body for PostCommentsModerationReject for application/json ContentType.
+type PostCommentsModerationRejectJSONRequestBody = ModerationRequest
+
 // PatchCommentsIdJSONRequestBody defines body for PatchCommentsId for application/json ContentType.
 type PatchCommentsIdJSONRequestBody = UpdateComment
 
@@ -280,12 +766,30 @@ type UploadImageMultipartRequestBody = ImageUploadRequest
 // PostLikesJSONRequestBody defines body for PostLikes for application/json ContentType.
 type PostLikesJSONRequestBody = LikeRequest
 
+// PutLikesJSONRequestBody defines body for PutLikes for application/json ContentType.
+type PutLikesJSONRequestBody = LikeStateRequest
+
+// PostReactionsJSONRequestBody defines body for PostReactions for application/json ContentType.
+type PostReactionsJSONRequestBody = ReactionRequest
+
+// PostSeriesJSONRequestBody defines body for PostSeries for application/json ContentType.
+type PostSeriesJSONRequestBody = NewSeries
+
+// PatchSeriesIdJSONRequestBody defines body for PatchSeriesId for application/json ContentType.
+type PatchSeriesIdJSONRequestBody = UpdateSeries
+
 // PostTagsJSONRequestBody defines body for PostTags for application/json ContentType.
 type PostTagsJSONRequestBody = Tag
 
+// PostTagsMergeJSONRequestBody defines body for PostTagsMerge for application/json ContentType.
+type PostTagsMergeJSONRequestBody = TagMergeRequest
+
 // PostTagsArticleIdJSONRequestBody defines body for PostTagsArticleId for application/json ContentType.
 type PostTagsArticleIdJSONRequestBody = TagRequest
 
+// PatchTagsIdJSONRequestBody defines body for PatchTagsId for application/json ContentType.
+type PatchTagsIdJSONRequestBody = TagUpdate
+
 // ServerInterface represents all server handlers.
 type ServerInterface interface {
 	// Get a list of articles
@@ -300,15 +804,36 @@ type ServerInterface interface {
 	// Get articles by author
 	// (GET /articles/author/{authorId})
 	GetArticlesAuthorAuthorId(ctx echo.Context, authorId string) error
+	// Get trending articles
+	// (GET /articles/trending)
+	GetArticlesTrending(ctx echo.Context, params GetArticlesTrendingParams) error
 	// Delete an article
 	// (DELETE /articles/{id})
 	DeleteArticlesId(ctx echo.Context, id string) error
 	// Get article details
 	// (GET /articles/{id})
-	GetArticlesId(ctx echo.Context, id string) error
+	GetArticlesId(ctx echo.Context, id string, params GetArticlesIdParams) error
 	// Update an article
 	// (PATCH /articles/{id})
 	PatchArticlesId(ctx echo.Context, id string) error
+	// Replace the tags of an article
+	// (PUT /articles/{id}/tags)
+	PutArticlesIdTags(ctx echo.Context, id string) error
+	// Remove a tag from an article
+	// (DELETE /articles/{id}/tags/{tagId})
+	DeleteArticlesIdTagsTagId(ctx echo.Context, id string, tagId string) error
+	// Add a tag to an article
+	// (POST /articles/{id}/tags/{tagId})
+	PostArticlesIdTagsTagId(ctx echo.Context, id string, tagId string) error
+	// Get translations of an article
+	// (GET /articles/{id}/translations)
+	GetArticlesIdTranslations(ctx echo.Context, id string) error
+	// Delete a translation
+	// (DELETE /articles/{id}/translations/{lang})
+	DeleteArticlesIdTranslationsLang(ctx echo.Context, id string, lang string) error
+	// Create or update a translation
+	// (PUT /articles/{id}/translations/{lang})
+	PutArticlesIdTranslationsLang(ctx echo.Context, id string, lang string) error
 	// Login
 	// (POST /auth/login)
 	PostAuthLogin(ctx echo.Context) error
@@ -318,6 +843,9 @@ type ServerInterface interface {
 	// Register a new user
 	// (POST /auth/register)
 	PostAuthRegister(ctx echo.Context) error
+	// Get read cache statistics
+	// (GET /cache/stats)
+	GetCacheStats(ctx echo.Context) error
 	// Get a list of categories
 	// (GET /categories)
 	GetCategories(ctx echo.Context) error
@@ -330,39 +858,114 @@ type ServerInterface interface {
 	// Post a comment
 	// (POST /comments)
 	PostComments(ctx echo.Context) error
+	// Get the comment moderation queue
+	// (GET /comments/moderation)
+	GetCommentsModeration(ctx echo.Context, params GetCommentsModerationParams) error
+	// Approve comments
+	// (POST /comments/moderation/approve)
+	PostCommentsModerationApprove(ctx echo.Context) error
+	// Reject comments
+	// (POST /comments/moderation/reject)
+	PostCommentsModerationReject(ctx echo.Context) error
+	// Stop reply notifications for a comment
+	// (GET /comments/subscriptions/{id}/unsubscribe)
+	GetCommentsSubscriptionsIdUnsubscribe(ctx echo.Context, id string, params GetCommentsSubscriptionsIdUnsubscribeParams) error
 	// Delete a comment
 	// (DELETE /comments/{id})
 	DeleteCommentsId(ctx echo.Context, id string) error
 	// Edit a comment
 	// (PATCH /comments/{id})
 	PatchCommentsId(ctx echo.Context, id string) error
+	// Get previous versions of a comment
+	// (GET /comments/{id}/revisions)
+	GetCommentsIdRevisions(ctx echo.Context, id string) error
 	// Submit a contact message
 	// (POST /contact)
 	PostContact(ctx echo.Context) error
+	// Get contact messages
+	// (GET /contact/messages)
+	GetContactMessages(ctx echo.Context, params GetContactMessagesParams) error
 	// Upload an image
 	// (POST /images/upload)
 	UploadImage(ctx echo.Context) error
+	// Remove a like from an article
+	// (DELETE /likes)
+	DeleteLikes(ctx echo.Context, params DeleteLikesParams) error
 	// Get likes for an article
 	// (GET /likes)
 	GetLikes(ctx echo.Context, params GetLikesParams) error
 	// Add a like to an article
 	// (POST /likes)
 	PostLikes(ctx echo.Context) error
+	// Set whether the visitor likes an article
+	// (PUT /likes)
+	PutLikes(ctx echo.Context) error
+	// Get like counts and states for several articles
+	// (GET /likes/status)
+	GetLikesStatus(ctx echo.Context, params GetLikesStatusParams) error
 	// Get profile information
 	// (GET /profile)
 	GetProfile(ctx echo.Context) error
+	// Remove a reaction
+	// (DELETE /reactions)
+	DeleteReactions(ctx echo.Context, params DeleteReactionsParams) error
+	// Get reactions for an article or comment
+	// (GET /reactions)
+	GetReactions(ctx echo.Context, params GetReactionsParams) error
+	// Add a reaction
+	// (POST /reactions)
+	PostReactions(ctx echo.Context) error
 	// Get RSS feed
 	// (GET /rss)
-	GetRss(ctx echo.Context) error
+	GetRss(ctx echo.Context, params GetRssParams) error
+	// Get a list of series
+	// (GET /series)
+	GetSeries(ctx echo.Context) error
+	// Create a new series
+	// (POST /series)
+	PostSeries(ctx echo.Context) error
+	// Delete a series
+	// (DELETE /series/{id})
+	DeleteSeriesId(ctx echo.Context, id string) error
+	// Get series details
+	// (GET /series/{id})
+	GetSeriesId(ctx echo.Context, id string) error
+	// Update a series
+	// (PATCH /series/{id})
+	PatchSeriesId(ctx echo.Context, id string) error
+	// Get RSS feed of a series
+	// (GET /series/{id}/rss)
+	GetSeriesIdRss(ctx echo.Context, id string) error
 	// Get a list of tags
 	// (GET /tags)
 	GetTags(ctx echo.Context) error
 	// Create a new tag
 	// (POST /tags)
 	PostTags(ctx echo.Context) error
+	// Merge tags
+	// (POST /tags/merge)
+	PostTagsMerge(ctx echo.Context) error
 	// Add tags to an article
 	// (POST /tags/{articleId})
 	PostTagsArticleId(ctx echo.Context, articleId string) error
+	// Delete a tag
+	// (DELETE /tags/{id})
+	DeleteTagsId(ctx echo.Context, id string) error
+	// Rename a tag
+	// (PATCH /tags/{id})
+	PatchTagsId(ctx echo.Context, id string) error
+	// Get trashed items
+	// (GET /trash)
+	GetTrash(ctx echo.Context) error
+	// Restore a trashed article
+	// (POST /trash/articles/{id}/restore)
+	PostTrashArticlesIdRestore(ctx echo.Context, id string) error
+	// Restore a trashed comment
+	// (POST /trash/comments/{id}/restore)
+	PostTrashCommentsIdRestore(ctx echo.Context, id string) error
+	// Restore a trashed tag
+	// (POST /trash/tags/{id}/restore)
+	PostTrashTagsIdRestore(ctx echo.Context, id string) error
 }
 
 // ServerInterfaceWrapper converts echo contexts to parameters.
@@ -418,6 +1021,13 @@ func (w *ServerInterfaceWrapper) GetArticles(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter order: %s", err))
 	}
 
+	// ------------- Optional query parameter "lang" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "lang", ctx.QueryParams(), &params.Lang)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter lang: %s", err))
+	}
+
 	// Invoke the callback with all the unmarshaled arguments
 	err = w.Handler.GetArticles(ctx, params)
 	return err
@@ -459,6 +1069,31 @@ func (w *ServerInterfaceWrapper) GetArticlesAuthorAuthorId(ctx echo.Context) err
 	return err
 }
 
+// GetArticlesTrending converts echo context to params.
+func (w *ServerInterfaceWrapper) GetArticlesTrending(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetArticlesTrendingParams
+	// ------------- Optional query parameter "window" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "window", ctx.QueryParams(), &params.Window)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter window: %s", err))
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", ctx.QueryParams(), &params.Limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter limit: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetArticlesTrending(ctx, params)
+	return err
+}
+
 // DeleteArticlesId converts echo context to params.
 func (w *ServerInterfaceWrapper) DeleteArticlesId(ctx echo.Context) error {
 	var err error
@@ -488,8 +1123,17 @@ func (w *ServerInterfaceWrapper) GetArticlesId(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
 	}
 
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetArticlesIdParams
+	// ------------- Optional query parameter "lang" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "lang", ctx.QueryParams(), &params.Lang)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter lang: %s", err))
+	}
+
 	// Invoke the callback with all the unmarshaled arguments
-	err = w.Handler.GetArticlesId(ctx, id)
+	err = w.Handler.GetArticlesId(ctx, id, params)
 	return err
 }
 
@@ -511,46 +1155,195 @@ func (w *ServerInterfaceWrapper) PatchArticlesId(ctx echo.Context) error {
 	return err
 }
 
-// PostAuthLogin converts echo context to params.
-func (w *ServerInterfaceWrapper) PostAuthLogin(ctx echo.Context) error {
+// PutArticlesIdTags converts echo context to params.
+func (w *ServerInterfaceWrapper) PutArticlesIdTags(ctx echo.Context) error {
 	var err error
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{})
 
 	// Invoke the callback with all the unmarshaled arguments
-	err = w.Handler.PostAuthLogin(ctx)
+	err = w.Handler.PutArticlesIdTags(ctx, id)
 	return err
 }
 
-// PostAuthLogout converts echo context to params.
-func (w *ServerInterfaceWrapper) PostAuthLogout(ctx echo.Context) error {
+// DeleteArticlesIdTagsTagId converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteArticlesIdTagsTagId(ctx echo.Context) error {
 	var err error
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// ------------- Path parameter "tagId" -------------
+	var tagId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "tagId", ctx.Param("tagId"), &tagId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter tagId: %s", err))
+	}
 
 	ctx.Set(BearerAuthScopes, []string{})
 
 	// Invoke the callback with all the unmarshaled arguments
-	err = w.Handler.PostAuthLogout(ctx)
+	err = w.Handler.DeleteArticlesIdTagsTagId(ctx, id, tagId)
 	return err
 }
 
-// PostAuthRegister converts echo context to params.
-func (w *ServerInterfaceWrapper) PostAuthRegister(ctx echo.Context) error {
+// PostArticlesIdTagsTagId converts echo context to params.
+func (w *ServerInterfaceWrapper) PostArticlesIdTagsTagId(ctx echo.Context) error {
 	var err error
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// ------------- Path parameter "tagId" -------------
+	var tagId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "tagId", ctx.Param("tagId"), &tagId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter tagId: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{})
 
 	// Invoke the callback with all the unmarshaled arguments
-	err = w.Handler.PostAuthRegister(ctx)
+	err = w.Handler.PostArticlesIdTagsTagId(ctx, id, tagId)
 	return err
 }
 
-// GetCategories converts echo context to params.
-func (w *ServerInterfaceWrapper) GetCategories(ctx echo.Context) error {
+// GetArticlesIdTranslations converts echo context to params.
+func (w *ServerInterfaceWrapper) GetArticlesIdTranslations(ctx echo.Context) error {
 	var err error
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
 
 	// Invoke the callback with all the unmarshaled arguments
-	err = w.Handler.GetCategories(ctx)
+	err = w.Handler.GetArticlesIdTranslations(ctx, id)
 	return err
 }
 
-// PostCategories converts echo context to params.
-func (w *ServerInterfaceWrapper) PostCategories(ctx echo.Context) error {
+// DeleteArticlesIdTranslationsLang converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteArticlesIdTranslationsLang(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// ------------- Path parameter "lang" -------------
+	var lang string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "lang", ctx.Param("lang"), &lang, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter lang: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.DeleteArticlesIdTranslationsLang(ctx, id, lang)
+	return err
+}
+
+// PutArticlesIdTranslationsLang converts echo context to params.
+func (w *ServerInterfaceWrapper) PutArticlesIdTranslationsLang(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// ------------- Path parameter "lang" -------------
+	var lang string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "lang", ctx.Param("lang"), &lang, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter lang: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PutArticlesIdTranslationsLang(ctx, id, lang)
+	return err
+}
+
+// PostAuthLogin converts echo context to params.
+func (w *ServerInterfaceWrapper) PostAuthLogin(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostAuthLogin(ctx)
+	return err
+}
+
+// PostAuthLogout converts echo context to params.
+func (w *ServerInterfaceWrapper) PostAuthLogout(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostAuthLogout(ctx)
+	return err
+}
+
+// PostAuthRegister converts echo context to params.
+func (w *ServerInterfaceWrapper) PostAuthRegister(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostAuthRegister(ctx)
+	return err
+}
+
+// GetCacheStats converts echo context to params.
+func (w *ServerInterfaceWrapper) GetCacheStats(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetCacheStats(ctx)
+	return err
+}
+
+// GetCategories converts echo context to params.
+func (w *ServerInterfaceWrapper) GetCategories(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetCategories(ctx)
+	return err
+}
+
+// PostCategories converts echo context to params.
+func (w *ServerInterfaceWrapper) PostCategories(ctx echo.Context) error {
 	var err error
 
 	// Invoke the callback with all the unmarshaled arguments
@@ -571,6 +1364,34 @@ func (w *ServerInterfaceWrapper) GetComments(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter articleId: %s", err))
 	}
 
+	// ------------- Optional query parameter "format" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "format", ctx.QueryParams(), &params.Format)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter format: %s", err))
+	}
+
+	// ------------- Optional query parameter "sort" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "sort", ctx.QueryParams(), &params.Sort)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter sort: %s", err))
+	}
+
+	// ------------- Optional query parameter "page" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "page", ctx.QueryParams(), &params.Page)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter page: %s", err))
+	}
+
+	// ------------- Optional query parameter "perPage" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "perPage", ctx.QueryParams(), &params.PerPage)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter perPage: %s", err))
+	}
+
 	// Invoke the callback with all the unmarshaled arguments
 	err = w.Handler.GetComments(ctx, params)
 	return err
@@ -585,6 +1406,73 @@ func (w *ServerInterfaceWrapper) PostComments(ctx echo.Context) error {
 	return err
 }
 
+// GetCommentsModeration converts echo context to params.
+func (w *ServerInterfaceWrapper) GetCommentsModeration(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetCommentsModerationParams
+	// ------------- Optional query parameter "status" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "status", ctx.QueryParams(), &params.Status)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter status: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetCommentsModeration(ctx, params)
+	return err
+}
+
+// PostCommentsModerationApprove converts echo context to params.
+func (w *ServerInterfaceWrapper) PostCommentsModerationApprove(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostCommentsModerationApprove(ctx)
+	return err
+}
+
+// PostCommentsModerationReject converts echo context to params.
+func (w *ServerInterfaceWrapper) PostCommentsModerationReject(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostCommentsModerationReject(ctx)
+	return err
+}
+
+// GetCommentsSubscriptionsIdUnsubscribe converts echo context to params.
+func (w *ServerInterfaceWrapper) GetCommentsSubscriptionsIdUnsubscribe(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetCommentsSubscriptionsIdUnsubscribeParams
+	// ------------- Required query parameter "token" -------------
+
+	err = runtime.BindQueryParameter("form", true, true, "token", ctx.QueryParams(), &params.Token)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter token: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetCommentsSubscriptionsIdUnsubscribe(ctx, id, params)
+	return err
+}
+
 // DeleteCommentsId converts echo context to params.
 func (w *ServerInterfaceWrapper) DeleteCommentsId(ctx echo.Context) error {
 	var err error
@@ -621,6 +1509,24 @@ func (w *ServerInterfaceWrapper) PatchCommentsId(ctx echo.Context) error {
 	return err
 }
 
+// GetCommentsIdRevisions converts echo context to params.
+func (w *ServerInterfaceWrapper) GetCommentsIdRevisions(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetCommentsIdRevisions(ctx, id)
+	return err
+}
+
 // PostContact converts echo context to params.
 func (w *ServerInterfaceWrapper) PostContact(ctx echo.Context) error {
 	var err error
@@ -630,6 +1536,26 @@ func (w *ServerInterfaceWrapper) PostContact(ctx echo.Context) error {
 	return err
 }
 
+// GetContactMessages converts echo context to params.
+func (w *ServerInterfaceWrapper) GetContactMessages(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetContactMessagesParams
+	// ------------- Optional query parameter "spam" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "spam", ctx.QueryParams(), &params.Spam)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter spam: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetContactMessages(ctx, params)
+	return err
+}
+
 // UploadImage converts echo context to params.
 func (w *ServerInterfaceWrapper) UploadImage(ctx echo.Context) error {
 	var err error
@@ -641,6 +1567,24 @@ func (w *ServerInterfaceWrapper) UploadImage(ctx echo.Context) error {
 	return err
 }
 
+// DeleteLikes converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteLikes(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params DeleteLikesParams
+	// ------------- Required query parameter "articleId" -------------
+
+	err = runtime.BindQueryParameter("form", true, true, "articleId", ctx.QueryParams(), &params.ArticleId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter articleId: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.DeleteLikes(ctx, params)
+	return err
+}
+
 // GetLikes converts echo context to params.
 func (w *ServerInterfaceWrapper) GetLikes(ctx echo.Context) error {
 	var err error
@@ -668,6 +1612,33 @@ func (w *ServerInterfaceWrapper) PostLikes(ctx echo.Context) error {
 	return err
 }
 
+// PutLikes converts echo context to params.
+func (w *ServerInterfaceWrapper) PutLikes(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PutLikes(ctx)
+	return err
+}
+
+// GetLikesStatus converts echo context to params.
+func (w *ServerInterfaceWrapper) GetLikesStatus(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetLikesStatusParams
+	// ------------- Required query parameter "articleIds" -------------
+
+	err = runtime.BindQueryParameter("form", true, true, "articleIds", ctx.QueryParams(), &params.ArticleIds)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter articleIds: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetLikesStatus(ctx, params)
+	return err
+}
+
 // GetProfile converts echo context to params.
 func (w *ServerInterfaceWrapper) GetProfile(ctx echo.Context) error {
 	var err error
@@ -677,12 +1648,169 @@ func (w *ServerInterfaceWrapper) GetProfile(ctx echo.Context) error {
 	return err
 }
 
+// DeleteReactions converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteReactions(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params DeleteReactionsParams
+	// ------------- Required query parameter "targetType" -------------
+
+	err = runtime.BindQueryParameter("form", true, true, "targetType", ctx.QueryParams(), &params.TargetType)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter targetType: %s", err))
+	}
+
+	// ------------- Required query parameter "targetId" -------------
+
+	err = runtime.BindQueryParameter("form", true, true, "targetId", ctx.QueryParams(), &params.TargetId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter targetId: %s", err))
+	}
+
+	// ------------- Required query parameter "emoji" -------------
+
+	err = runtime.BindQueryParameter("form", true, true, "emoji", ctx.QueryParams(), &params.Emoji)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter emoji: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.DeleteReactions(ctx, params)
+	return err
+}
+
+// GetReactions converts echo context to params.
+func (w *ServerInterfaceWrapper) GetReactions(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetReactionsParams
+	// ------------- Required query parameter "targetType" -------------
+
+	err = runtime.BindQueryParameter("form", true, true, "targetType", ctx.QueryParams(), &params.TargetType)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter targetType: %s", err))
+	}
+
+	// ------------- Required query parameter "targetId" -------------
+
+	err = runtime.BindQueryParameter("form", true, true, "targetId", ctx.QueryParams(), &params.TargetId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter targetId: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetReactions(ctx, params)
+	return err
+}
+
+// PostReactions converts echo context to params.
+func (w *ServerInterfaceWrapper) PostReactions(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostReactions(ctx)
+	return err
+}
+
 // GetRss converts echo context to params.
 func (w *ServerInterfaceWrapper) GetRss(ctx echo.Context) error {
 	var err error
 
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetRssParams
+	// ------------- Optional query parameter "lang" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "lang", ctx.QueryParams(), &params.Lang)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter lang: %s", err))
+	}
+
 	// Invoke the callback with all the unmarshaled arguments
-	err = w.Handler.GetRss(ctx)
+	err = w.Handler.GetRss(ctx, params)
+	return err
+}
+
+// GetSeries converts echo context to params.
+func (w *ServerInterfaceWrapper) GetSeries(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetSeries(ctx)
+	return err
+}
+
+// PostSeries converts echo context to params.
+func (w *ServerInterfaceWrapper) PostSeries(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostSeries(ctx)
+	return err
+}
+
+// DeleteSeriesId converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteSeriesId(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.DeleteSeriesId(ctx, id)
+	return err
+}
+
+// GetSeriesId converts echo context to params.
+func (w *ServerInterfaceWrapper) GetSeriesId(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetSeriesId(ctx, id)
+	return err
+}
+
+// PatchSeriesId converts echo context to params.
+func (w *ServerInterfaceWrapper) PatchSeriesId(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PatchSeriesId(ctx, id)
+	return err
+}
+
+// GetSeriesIdRss converts echo context to params.
+func (w *ServerInterfaceWrapper) GetSeriesIdRss(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetSeriesIdRss(ctx, id)
 	return err
 }
 
@@ -704,6 +1832,17 @@ func (w *ServerInterfaceWrapper) PostTags(ctx echo.Context) error {
 	return err
 }
 
+// PostTagsMerge converts echo context to params.
+func (w *ServerInterfaceWrapper) PostTagsMerge(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostTagsMerge(ctx)
+	return err
+}
+
 // PostTagsArticleId converts echo context to params.
 func (w *ServerInterfaceWrapper) PostTagsArticleId(ctx echo.Context) error {
 	var err error
@@ -720,6 +1859,107 @@ func (w *ServerInterfaceWrapper) PostTagsArticleId(ctx echo.Context) error {
 	return err
 }
 
+// DeleteTagsId converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteTagsId(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.DeleteTagsId(ctx, id)
+	return err
+}
+
+// PatchTagsId converts echo context to params.
+func (w *ServerInterfaceWrapper) PatchTagsId(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PatchTagsId(ctx, id)
+	return err
+}
+
+// GetTrash converts echo context to params.
+func (w *ServerInterfaceWrapper) GetTrash(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetTrash(ctx)
+	return err
+}
+
+// PostTrashArticlesIdRestore converts echo context to params.
+func (w *ServerInterfaceWrapper) PostTrashArticlesIdRestore(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostTrashArticlesIdRestore(ctx, id)
+	return err
+}
+
+// PostTrashCommentsIdRestore converts echo context to params.
+func (w *ServerInterfaceWrapper) PostTrashCommentsIdRestore(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostTrashCommentsIdRestore(ctx, id)
+	return err
+}
+
+// PostTrashTagsIdRestore converts echo context to params.
+func (w *ServerInterfaceWrapper) PostTrashTagsIdRestore(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostTrashTagsIdRestore(ctx, id)
+	return err
+}
+
 // This is a simple interface which specifies echo.Route addition functions which
 // are present on both echo.Echo and echo.Group, since we want to allow using
 // either of them for path registration
@@ -752,26 +1992,59 @@ func RegisterHandlersWithBaseURL(router EchoRouter, si ServerInterface, baseURL
 	router.POST(baseURL+"/articles", wrapper.PostArticles)
 	router.GET(baseURL+"/articles/archive", wrapper.GetArticlesArchive)
 	router.GET(baseURL+"/articles/author/:authorId", wrapper.GetArticlesAuthorAuthorId)
+	router.GET(baseURL+"/articles/trending", wrapper.GetArticlesTrending)
 	router.DELETE(baseURL+"/articles/:id", wrapper.DeleteArticlesId)
 	router.GET(baseURL+"/articles/:id", wrapper.GetArticlesId)
 	router.PATCH(baseURL+"/articles/:id", wrapper.PatchArticlesId)
+	router.PUT(baseURL+"/articles/:id/tags", wrapper.PutArticlesIdTags)
+	router.DELETE(baseURL+"/articles/:id/tags/:tagId", wrapper.DeleteArticlesIdTagsTagId)
+	router.POST(baseURL+"/articles/:id/tags/:tagId", wrapper.PostArticlesIdTagsTagId)
+	router.GET(baseURL+"/articles/:id/translations", wrapper.GetArticlesIdTranslations)
+	router.DELETE(baseURL+"/articles/:id/translations/:lang", wrapper.DeleteArticlesIdTranslationsLang)
+	router.PUT(baseURL+"/articles/:id/translations/:lang", wrapper.PutArticlesIdTranslationsLang)
 	router.POST(baseURL+"/auth/login", wrapper.PostAuthLogin)
 	router.POST(baseURL+"/auth/logout", wrapper.PostAuthLogout)
 	router.POST(baseURL+"/auth/register", wrapper.PostAuthRegister)
+	router.GET(baseURL+"/cache/stats", wrapper.GetCacheStats)
 	router.GET(baseURL+"/categories", wrapper.GetCategories)
 	router.POST(baseURL+"/categories", wrapper.PostCategories)
 	router.GET(baseURL+"/comments", wrapper.GetComments)
 	router.POST(baseURL+"/comments", wrapper.PostComments)
+	router.GET(baseURL+"/comments/moderation", wrapper.GetCommentsModeration)
+	router.POST(baseURL+"/comments/moderation/approve", wrapper.PostCommentsModerationApprove)
+	router.POST(baseURL+"/comments/moderation/reject", wrapper.PostCommentsModerationReject)
+	router.GET(baseURL+"/comments/subscriptions/:id/unsubscribe", wrapper.GetCommentsSubscriptionsIdUnsubscribe)
 	router.DELETE(baseURL+"/comments/:id", wrapper.DeleteCommentsId)
 	router.PATCH(baseURL+"/comments/:id", wrapper.PatchCommentsId)
+	router.GET(baseURL+"/comments/:id/revisions", wrapper.GetCommentsIdRevisions)
 	router.POST(baseURL+"/contact", wrapper.PostContact)
+	router.GET(baseURL+"/contact/messages", wrapper.GetContactMessages)
 	router.POST(baseURL+"/images/upload", wrapper.UploadImage)
+	router.DELETE(baseURL+"/likes", wrapper.DeleteLikes)
 	router.GET(baseURL+"/likes", wrapper.GetLikes)
 	router.POST(baseURL+"/likes", wrapper.PostLikes)
+	router.PUT(baseURL+"/likes", wrapper.PutLikes)
+	router.GET(baseURL+"/likes/status", wrapper.GetLikesStatus)
 	router.GET(baseURL+"/profile", wrapper.GetProfile)
+	router.DELETE(baseURL+"/reactions", wrapper.DeleteReactions)
+	router.GET(baseURL+"/reactions", wrapper.GetReactions)
+	router.POST(baseURL+"/reactions", wrapper.PostReactions)
 	router.GET(baseURL+"/rss", wrapper.GetRss)
+	router.GET(baseURL+"/series", wrapper.GetSeries)
+	router.POST(baseURL+"/series", wrapper.PostSeries)
+	router.DELETE(baseURL+"/series/:id", wrapper.DeleteSeriesId)
+	router.GET(baseURL+"/series/:id", wrapper.GetSeriesId)
+	router.PATCH(baseURL+"/series/:id", wrapper.PatchSeriesId)
+	router.GET(baseURL+"/series/:id/rss", wrapper.GetSeriesIdRss)
 	router.GET(baseURL+"/tags", wrapper.GetTags)
 	router.POST(baseURL+"/tags", wrapper.PostTags)
+	router.POST(baseURL+"/tags/merge", wrapper.PostTagsMerge)
 	router.POST(baseURL+"/tags/:articleId", wrapper.PostTagsArticleId)
+	router.DELETE(baseURL+"/tags/:id", wrapper.DeleteTagsId)
+	router.PATCH(baseURL+"/tags/:id", wrapper.PatchTagsId)
+	router.GET(baseURL+"/trash", wrapper.GetTrash)
+	router.POST(baseURL+"/trash/articles/:id/restore", wrapper.PostTrashArticlesIdRestore)
+	router.POST(baseURL+"/trash/comments/:id/restore", wrapper.PostTrashCommentsIdRestore)
+	router.POST(baseURL+"/trash/tags/:id/restore", wrapper.PostTrashTagsIdRestore)
 
 }
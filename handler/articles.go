@@ -5,6 +5,8 @@ import (
 	"blog-backend/logger"
 	"blog-backend/model"
 	"database/sql"
+	"encoding/json"
+	"io"
 	"net/http"
 	"os"
 	"sort"
@@ -17,6 +19,12 @@ import (
 // articles per page
 const articlesPerPage = 10
 
+// トレンドの記事の数 (全体とカテゴリーごと)
+const (
+	trendingLimit    = 10
+	maxTrendingLimit = 50
+)
+
 // Get a list of articles
 // (GET /articles)
 func (h *Handler) GetArticles(ctx echo.Context, params api.GetArticlesParams) error {
@@ -46,14 +54,30 @@ func (h *Handler) GetArticles(ctx echo.Context, params api.GetArticlesParams) er
 	}
 	orderby := "created_at"
 	order := "desc"
-	if params.Orderby != nil && *params.Orderby == api.ViewCount {
-		orderby = "view_count"
+	if params.Orderby != nil {
+		switch *params.Orderby {
+		case api.ViewCount, api.LikeCount, api.CommentCount:
+			orderby = string(*params.Orderby)
+		}
 	}
 	if params.Order != nil && *params.Order == api.Asc {
 		order = "asc"
 	}
 
-	articles, err := h.Repo.GetArticlesByCategoryTagSearch(ctx.Request().Context(), &category_id, &tag_id, params.Search, &articlesLength, orderby, order)
+	lang := ""
+	if params.Lang != nil {
+		lang = model.NormalizeLanguage(*params.Lang)
+	}
+
+	articles, err := h.Repo.GetArticlesByFilter(ctx.Request().Context(), model.ArticleFilter{
+		CategoryID: category_id,
+		TagID:      tag_id,
+		Search:     params.Search,
+		Lang:       lang,
+		Limit:      articlesLength,
+		OrderBy:    orderby,
+		Order:      order,
+	})
 	if err != nil {
 		return ctx.JSON(http.StatusInternalServerError, err)
 	}
@@ -66,8 +90,10 @@ func (h *Handler) GetArticles(ctx echo.Context, params api.GetArticlesParams) er
 		logger.Println("Convert articles error: ", err)
 		return ctx.JSON(http.StatusInternalServerError, err)
 	}
+	// convertArticlesToAPIArticlesは作成日時の降順に並べ替えるので、指定された順に戻す
+	sortAPIArticles(apiArticles, orderby, order)
 
-	return ctx.JSON(http.StatusOK, api.ArticleResponse{
+	body, err := json.Marshal(api.ArticleResponse{
 		Articles: &apiArticles,
 		Condition: &struct {
 			Category *string `json:"category,omitempty"`
@@ -83,6 +109,13 @@ func (h *Handler) GetArticles(ctx echo.Context, params api.GetArticlesParams) er
 			Search:   params.Search,
 		},
 	})
+	if err != nil {
+		return ctx.JSON(http.StatusInternalServerError, err)
+	}
+	// 記事の削除や並び替えは各記事のupdated_atに表れないため、一覧にはLast-Modifiedを付けずETagだけで検証する
+	ctx.Response().Header().Add(echo.HeaderVary, "Accept-Language")
+	ctx.Response().Header().Add(echo.HeaderVary, echo.HeaderAuthorization)
+	return respondWithValidators(ctx, body, weakContentETag(body), time.Time{}, articleCacheControl)
 }
 
 // Create a new article
@@ -125,6 +158,14 @@ func (h *Handler) PostArticles(ctx echo.Context) error {
 		tags[i].Name = tagName.Name
 	}
 
+	lang := model.DefaultLanguage()
+	if req.Lang != nil {
+		lang = model.NormalizeLanguage(*req.Lang)
+		if !model.IsSupportedLanguage(lang) {
+			return ctx.JSON(http.StatusBadRequest, "Unsupported language")
+		}
+	}
+
 	articleId := uuid.New()
 
 	// // req.AuthorIdが存在しない場合はIPアドレスでユーザーを特定する
@@ -213,6 +254,7 @@ func (h *Handler) PostArticles(ctx echo.Context) error {
 		CreatedAt:  time.Now(),
 		UpdatedAt:  time.Now(),
 		ViewCount:  sql.NullInt64{Int64: 0, Valid: true},
+		Lang:       lang,
 	}
 
 	article, err := h.Repo.CreateArticle(ctx.Request().Context(), newArticle)
@@ -293,6 +335,12 @@ func (h *Handler) PostArticles(ctx echo.Context) error {
 			logger.Printf("Failed to generate RSS feed: %v", err)
 			// return ctx.JSON(http.StatusInternalServerError, err)
 		}
+
+		// 言語ごとのRSSフィードも更新
+		err = h.Config.LanguageRSSmaker(ctx.Request().Context(), h.Repo)
+		if err != nil {
+			logger.Printf("Failed to generate language RSS feeds: %v", err)
+		}
 	}
 
 	return nil
@@ -306,18 +354,34 @@ func (h *Handler) DeleteArticlesId(ctx echo.Context, id string) error {
 	if err != nil {
 		return ctx.JSON(http.StatusBadRequest, err)
 	}
-	err = h.Repo.DeleteArticle(ctx.Request().Context(), articleId)
+	article, err := h.Repo.GetArticleByID(ctx.Request().Context(), articleId)
+	if err == sql.ErrNoRows {
+		return ctx.JSON(http.StatusNotFound, "Article not found")
+	}
+	if err != nil {
+		return ctx.JSON(http.StatusInternalServerError, err)
+	}
+	if ok, err := checkArticleIfMatch(ctx, article.Version); !ok {
+		return err
+	}
+	err = h.Repo.DeleteArticle(ctx.Request().Context(), articleId, article.Version)
+	if err == model.ErrVersionConflict {
+		return h.currentArticleVersionConflict(ctx, articleId)
+	}
 	if err != nil {
 		return ctx.JSON(http.StatusInternalServerError, err)
 	}
+	// ゴミ箱に入れた記事をRSSフィードから外す
+	h.regenerateFeeds()
 	return ctx.JSON(http.StatusNoContent, nil)
 }
 
 // Get article details
 // (GET /articles/{id})
-func (h *Handler) GetArticlesId(ctx echo.Context, id string) error {
-	saveAnalysis := func(ctx echo.Context, articleId uuid.UUID, api string, isError bool) error {
-		err := h.Repo.CreateAnalysis(ctx.Request().Context(), model.Analysis{
+func (h *Handler) GetArticlesId(ctx echo.Context, id string, params api.GetArticlesIdParams) error {
+	// 閲覧の記録はViewWriterに溜めてまとめて書き込む。レスポンスはDBへの書き込みを待たない
+	saveAnalysis := func(ctx echo.Context, articleId uuid.UUID, api string, isError bool) {
+		h.Views.EnqueueAnalysis(model.Analysis{
 			ID:         uuid.New(),
 			Timestamp:  time.Now(),
 			ArticleID:  articleId,
@@ -326,23 +390,6 @@ func (h *Handler) GetArticlesId(ctx echo.Context, id string) error {
 			API:        api,
 			IsError:    isError,
 		})
-		if err != nil {
-			return err
-		}
-		if !isError {
-			logger.Println("SaveAnalysis Error: ")
-			err = h.Repo.SaveViewCount(ctx.Request().Context(), articleId)
-			if err != nil {
-				return err
-			}
-			// get article view count
-			article, err := h.Repo.GetArticleByID(ctx.Request().Context(), articleId)
-			if err != nil {
-				return err
-			}
-			logger.Println("ViewCount: ", article.ViewCount)
-		}
-		return nil
 	}
 
 	articleId, err := uuid.Parse(id)
@@ -350,37 +397,45 @@ func (h *Handler) GetArticlesId(ctx echo.Context, id string) error {
 		return ctx.JSON(http.StatusBadRequest, err)
 	}
 
-	err = h.Repo.SaveViewCount(ctx.Request().Context(), articleId)
+	article, err := h.Repo.GetArticleByID(ctx.Request().Context(), articleId)
+	if err == sql.ErrNoRows {
+		return ctx.JSON(http.StatusNotFound, "Article not found")
+	}
 	if err != nil {
-		errSaveAnalysis := saveAnalysis(ctx, articleId, "GetArticlesId", true)
-		if errSaveAnalysis != nil {
-			logger.Println("SaveAnalysis Error: ", errSaveAnalysis)
-		}
+		saveAnalysis(ctx, articleId, "GetArticlesId", true)
 		return ctx.JSON(http.StatusInternalServerError, err)
 	}
 
-	article, err := h.Repo.GetArticleByID(ctx.Request().Context(), articleId)
+	// アクセス数は毎回、閲覧数はボットを除いて同じ訪問者を一定時間に1回だけ数える
+	// 書き込みは数秒ごとにまとめて行うため、返すview_countには今回の閲覧はまだ含まれない
+	userAgent := ctx.Request().UserAgent()
+	h.Views.EnqueueView(model.ArticleView{
+		ArticleID:   articleId,
+		VisitorHash: model.VisitorHash(ctx.RealIP(), userAgent),
+		IsBot:       model.IsBotUserAgent(userAgent),
+		ViewedAt:    time.Now(),
+	})
+	articles := []model.Article{article}
+	apiArticle, err := convertArticlesToAPIArticles(ctx, articles, h.Repo)
 	if err != nil {
-		errSaveAnalysis := saveAnalysis(ctx, articleId, "GetArticlesId", true)
-		if errSaveAnalysis != nil {
-			logger.Println("SaveAnalysis Error: ", errSaveAnalysis)
-		}
+		saveAnalysis(ctx, articleId, "GetArticlesId", true)
 		return ctx.JSON(http.StatusInternalServerError, err)
 	}
-	articles := []model.Article{article}
-	apiArticle, err := convertArticlesToAPIArticles(ctx, articles, h.Repo)
+	saveAnalysis(ctx, articleId, "GetArticlesId", false)
+	// 返す言語はAccept-Languageによって、いいね・リアクションの状態は閲覧者によって変わる
+	ctx.Response().Header().Add(echo.HeaderVary, "Accept-Language")
+	ctx.Response().Header().Add(echo.HeaderVary, echo.HeaderAuthorization)
+	ctx.Response().Header().Set("Content-Language", *apiArticle[0].Lang)
+	etag, err := articleETag(article.Version, apiArticle[0])
 	if err != nil {
-		errSaveAnalysis := saveAnalysis(ctx, articleId, "GetArticlesId", true)
-		if errSaveAnalysis != nil {
-			logger.Println("SaveAnalysis Error: ", errSaveAnalysis)
-		}
 		return ctx.JSON(http.StatusInternalServerError, err)
 	}
-	errSaveAnalysis := saveAnalysis(ctx, articleId, "GetArticlesId", false)
-	if errSaveAnalysis != nil {
-		logger.Println("SaveAnalysis Error: ", errSaveAnalysis)
+	body, err := json.Marshal(apiArticle[0])
+	if err != nil {
+		return ctx.JSON(http.StatusInternalServerError, err)
 	}
-	return ctx.JSON(http.StatusOK, apiArticle[0])
+	// いいねやリアクションはupdated_atを変えないため、Last-Modifiedは付けずETagだけで検証する
+	return respondWithValidators(ctx, body, etag, time.Time{}, articleCacheControl)
 }
 
 // Update an article
@@ -391,41 +446,197 @@ func (h *Handler) PatchArticlesId(ctx echo.Context, id string) error {
 		return ctx.JSON(http.StatusBadRequest, err)
 	}
 
+	// JSON Merge Patchとして扱う。省略されたフィールドとnullを区別するため、ボディを2回デコードする
+	body, err := io.ReadAll(ctx.Request().Body)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, err)
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return ctx.JSON(http.StatusBadRequest, "request body must be a JSON object")
+	}
 	var req api.PatchArticlesIdJSONRequestBody
-	if err := ctx.Bind(&req); err != nil {
+	if err := json.Unmarshal(body, &req); err != nil {
 		return ctx.JSON(http.StatusBadRequest, err)
 	}
+	for _, field := range []string{"title", "content", "author_id", "category"} {
+		if value, ok := fields[field]; ok && string(value) == "null" {
+			return ctx.JSON(http.StatusBadRequest, field+" cannot be removed")
+		}
+	}
 
-	// add category
-	categoryId, err := h.Repo.AddCategory(ctx.Request().Context(), *req.Category)
+	article, err := h.Repo.GetArticleByID(ctx.Request().Context(), articleId)
+	if err == sql.ErrNoRows {
+		return ctx.JSON(http.StatusNotFound, "Article not found")
+	}
 	if err != nil {
 		return ctx.JSON(http.StatusInternalServerError, err)
 	}
+	if ok, err := checkArticleIfMatch(ctx, article.Version); !ok {
+		return err
+	}
 
-	// add tags
-	tags := make([]model.Tag, 0)
-	for _, tag := range *req.Tags {
-		tags = append(tags, model.Tag{
-			ID: uuid.MustParse(tag),
-		})
+	// サムネイルに描かれる項目が変わった場合はサムネイルを作り直す
+	thumbnailChanged := false
+	feedChanged := false
+	if req.Title != nil {
+		if *req.Title == "" {
+			return ctx.JSON(http.StatusBadRequest, "title must not be empty")
+		}
+		if *req.Title != article.Title {
+			article.Title = *req.Title
+			thumbnailChanged = true
+			feedChanged = true
+		}
+	}
+	if req.Content != nil {
+		if *req.Content == "" {
+			return ctx.JSON(http.StatusBadRequest, "content must not be empty")
+		}
+		if *req.Content != article.Content {
+			article.Content = *req.Content
+			feedChanged = true
+		}
+	}
+	if req.AuthorId != nil {
+		authorId, err := uuid.Parse(*req.AuthorId)
+		if err != nil {
+			return ctx.JSON(http.StatusBadRequest, "invalid author_id")
+		}
+		if _, err := h.Repo.GetUserByID(ctx.Request().Context(), authorId); err != nil {
+			return ctx.JSON(http.StatusBadRequest, "author not found")
+		}
+		if authorId != article.AuthorID {
+			article.AuthorID = authorId
+			thumbnailChanged = true
+		}
+	}
+	// POSTと同様に、categoryはカテゴリーのIDとして扱う
+	if req.Category != nil {
+		categoryId, err := uuid.Parse(*req.Category)
+		if err != nil {
+			return ctx.JSON(http.StatusBadRequest, "invalid category")
+		}
+		if _, err := h.Repo.AddCategoryID(ctx.Request().Context(), categoryId); err != nil {
+			return ctx.JSON(http.StatusBadRequest, "category not found")
+		}
+		if categoryId != article.CategoryID {
+			article.CategoryID = categoryId
+			thumbnailChanged = true
+			feedChanged = true
+		}
+	}
+
+	// tagsがnullの場合はすべて外し、省略された場合はnilのままにして変更しない
+	var tagIDs []uuid.UUID
+	if value, ok := fields["tags"]; ok {
+		tagIDs = make([]uuid.UUID, 0)
+		if string(value) != "null" && req.Tags != nil {
+			seen := make(map[uuid.UUID]bool)
+			for _, tag := range *req.Tags {
+				tagId, err := uuid.Parse(tag)
+				if err != nil {
+					return ctx.JSON(http.StatusBadRequest, "invalid tag id: "+tag)
+				}
+				if seen[tagId] {
+					continue
+				}
+				seen[tagId] = true
+				if _, err := h.Repo.GetTagItemsByID(ctx.Request().Context(), tagId); err != nil {
+					return ctx.JSON(http.StatusBadRequest, "tag not found: "+tag)
+				}
+				tagIDs = append(tagIDs, tagId)
+			}
+		}
+		thumbnailChanged = true
+		feedChanged = true
+	}
+
+	article.UpdatedAt = time.Now()
+	article, err = h.Repo.UpdateArticleWithTags(ctx.Request().Context(), article, tagIDs)
+	if err == model.ErrVersionConflict {
+		// If-Matchの確認後に他の編集が入った
+		return h.currentArticleVersionConflict(ctx, articleId)
 	}
-	err = h.Repo.AddTags(ctx.Request().Context(), tags)
 	if err != nil {
+		logger.Println("UpdateArticleWithTags Error: ", err)
 		return ctx.JSON(http.StatusInternalServerError, err)
 	}
 
-	article, err := h.Repo.UpdateArticle(ctx.Request().Context(), model.Article{
-		ID:         articleId,
-		Title:      *req.Title,
-		Content:    *req.Content,
-		AuthorID:   uuid.MustParse(*req.AuthorId),
-		CategoryID: categoryId.ID,
-		UpdatedAt:  time.Now(),
-	})
+	// 記事はすでに保存されているので、サムネイルを作り直せなくても更新は成功として古い画像のまま返す
+	if thumbnailChanged {
+		imageUrl, err := h.regenerateThumbnail(ctx, article)
+		if err != nil {
+			logger.Println("HandleThumbnailGeneration Error: ", err)
+		} else {
+			article.ImageURL = sql.NullString{String: imageUrl, Valid: imageUrl != ""}
+		}
+	}
+	if feedChanged {
+		h.regenerateFeeds()
+	}
+
+	apiArticles, err := convertArticlesToAPIArticles(ctx, []model.Article{article}, h.Repo)
+	if err != nil {
+		logger.Println("Convert articles error: ", err)
+		return ctx.JSON(http.StatusInternalServerError, err)
+	}
+	etag, err := articleETag(article.Version, apiArticles[0])
+	if err != nil {
+		return ctx.JSON(http.StatusInternalServerError, err)
+	}
+	ctx.Response().Header().Set("ETag", etag)
+	return ctx.JSON(http.StatusOK, apiArticles[0])
+}
+
+// currentArticleVersionConflict - 最新の版数を読み直して412を返す
+func (h *Handler) currentArticleVersionConflict(ctx echo.Context, articleId uuid.UUID) error {
+	current, err := h.Repo.GetArticleByID(ctx.Request().Context(), articleId)
+	if err == sql.ErrNoRows {
+		return ctx.JSON(http.StatusNotFound, "Article not found")
+	}
 	if err != nil {
 		return ctx.JSON(http.StatusInternalServerError, err)
 	}
-	return ctx.JSON(http.StatusOK, article)
+	return articleVersionConflict(ctx, current.Version)
+}
+
+// regenerateThumbnail - 記事の現在のタグ・カテゴリー・著者でサムネイルを作り直し、新しい画像のURLを返す
+func (h *Handler) regenerateThumbnail(ctx echo.Context, article model.Article) (string, error) {
+	tags, err := h.Repo.GetTagsByArticle(ctx.Request().Context(), article.ID, nil)
+	if err != nil {
+		return "", err
+	}
+	tagItems := make([]model.TagItem, 0, len(tags))
+	for _, tag := range tags {
+		tagItems = append(tagItems, model.TagItem{ID: tag.ID, Name: tag.Name})
+	}
+	category, err := h.Repo.GetCategoryNameByID(ctx.Request().Context(), article.CategoryID)
+	if err != nil {
+		return "", err
+	}
+	author, err := h.Repo.GetUserNameById(ctx.Request().Context(), article.AuthorID)
+	if err != nil {
+		return "", err
+	}
+	authorName := author.Username.String
+	if authorName == "" {
+		authorName = "Luftalian"
+	}
+	imageUrl, imagePath, imageFileName, err := h.Config.HandleThumbnailGeneration(ctx.Request().Context(), article, tagItems, category.Name, authorName)
+	if err != nil {
+		return "", err
+	}
+	if imageUrl != "" {
+		err = h.Repo.UpdateArticleImageURL(ctx.Request().Context(), article.ID, imageUrl)
+		if err != nil {
+			return "", err
+		}
+	}
+	if h.DriveService != nil {
+		model.UploadAsyncToDrive(h.DriveService, imagePath, imageFileName, os.Getenv("DRIVE_FOLDER_ID"))
+	}
+	return imageUrl, nil
 }
 
 // Get archive of articles
@@ -456,3 +667,100 @@ func (h *Handler) GetArticlesAuthorAuthorId(ctx echo.Context, authorId string) e
 		AuthorId: &authorId,
 	})
 }
+
+// Get trending articles
+// (GET /articles/trending)
+func (h *Handler) GetArticlesTrending(ctx echo.Context, params api.GetArticlesTrendingParams) error {
+	period := model.TrendingPeriodWeek
+	if params.Window != nil {
+		period = string(*params.Window)
+	}
+	if !model.ValidTrendingPeriod(period) {
+		return ctx.JSON(http.StatusBadRequest, "window must be 24h, 7d or 30d")
+	}
+	limit := trendingLimit
+	if params.Limit != nil && *params.Limit > 0 {
+		limit = min(*params.Limit, maxTrendingLimit)
+	}
+	scores, err := h.Repo.GetTrendingScores(ctx.Request().Context(), period)
+	if err != nil {
+		logger.Println("GetTrendingScores Error: ", err)
+		return ctx.JSON(http.StatusInternalServerError, err)
+	}
+
+	// スコアの高い順に、全体とカテゴリーごとの上位limit件を選ぶ
+	overall := make([]model.TrendingScore, 0, limit)
+	byCategory := make(map[uuid.UUID][]model.TrendingScore)
+	categoryOrder := make([]uuid.UUID, 0)
+	selected := make([]uuid.UUID, 0)
+	seen := make(map[uuid.UUID]bool)
+	for _, score := range scores {
+		use := false
+		if len(overall) < limit {
+			overall = append(overall, score)
+			use = true
+		}
+		if _, ok := byCategory[score.CategoryID]; !ok {
+			categoryOrder = append(categoryOrder, score.CategoryID)
+		}
+		if len(byCategory[score.CategoryID]) < limit {
+			byCategory[score.CategoryID] = append(byCategory[score.CategoryID], score)
+			use = true
+		}
+		if use && !seen[score.ArticleID] {
+			seen[score.ArticleID] = true
+			selected = append(selected, score.ArticleID)
+		}
+	}
+
+	articles, err := h.Repo.GetArticlesByIDs(ctx.Request().Context(), selected)
+	if err != nil {
+		logger.Println("GetArticlesByIDs Error: ", err)
+		return ctx.JSON(http.StatusInternalServerError, err)
+	}
+	apiArticles, err := convertArticlesToAPIArticles(ctx, articles, h.Repo)
+	if err != nil {
+		return ctx.JSON(http.StatusInternalServerError, err)
+	}
+	articleByID := make(map[string]api.Article)
+	for _, article := range apiArticles {
+		articleByID[*article.Id] = article
+	}
+	toAPI := func(scores []model.TrendingScore) []api.TrendingArticle {
+		trending := make([]api.TrendingArticle, 0, len(scores))
+		for _, score := range scores {
+			article, ok := articleByID[score.ArticleID.String()]
+			if !ok {
+				continue
+			}
+			value := score.Score
+			trending = append(trending, api.TrendingArticle{
+				Article: &article,
+				Score:   &value,
+			})
+		}
+		return trending
+	}
+
+	apiOverall := toAPI(overall)
+	categories := make([]api.TrendingCategory, 0, len(categoryOrder))
+	for _, categoryID := range categoryOrder {
+		categoryArticles := toAPI(byCategory[categoryID])
+		if len(categoryArticles) == 0 {
+			continue
+		}
+		categories = append(categories, api.TrendingCategory{
+			Category: categoryArticles[0].Article.Category,
+			Articles: &categoryArticles,
+		})
+	}
+	response := api.TrendingResponse{
+		Window:     &period,
+		Overall:    &apiOverall,
+		Categories: &categories,
+	}
+	if len(scores) > 0 {
+		response.ComputedAt = &scores[0].ComputedAt
+	}
+	return ctx.JSON(http.StatusOK, response)
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
+	openapi_types "github.com/oapi-codegen/runtime/types"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -19,15 +20,33 @@ func (h *Handler) PostAuthLogin(ctx echo.Context) error {
 	if err := ctx.Bind(&req); err != nil {
 		return ctx.JSON(http.StatusBadRequest, err)
 	}
-	// check Email and password
-	user, err := h.Repo.GetUserByEmailAndPassword(ctx.Request().Context(), string(req.Email), string(req.Password))
+	// check Email and password (登録時にbcryptでハッシュ化している)
+	user, err := h.Repo.GetUserByEmail(ctx.Request().Context(), string(req.Email))
+	if err == sql.ErrNoRows {
+		return ctx.JSON(http.StatusUnauthorized, "invalid email or password")
+	}
 	if err != nil {
 		return ctx.JSON(http.StatusInternalServerError, err)
 	}
-	if user.ID == uuid.Nil {
+	if !user.PasswordHash.Valid || bcrypt.CompareHashAndPassword([]byte(user.PasswordHash.String), []byte(req.Password)) != nil {
 		return ctx.JSON(http.StatusUnauthorized, "invalid email or password")
 	}
-	return ctx.JSON(http.StatusOK, user)
+	// 公開してよいユーザーの情報に、コメントの編集などで使うBearerトークンを加えて返す
+	// パスワードのハッシュやIPアドレス、管理者かどうかは返さない
+	expiresAt := time.Now().Add(authTokenTTL())
+	res := api.LoginResponse{
+		Id:        user.ID.String(),
+		Token:     issueAuthToken(user.ID, expiresAt),
+		ExpiresAt: expiresAt,
+	}
+	if user.Email.Valid {
+		email := openapi_types.Email(user.Email.String)
+		res.Email = &email
+	}
+	if user.Username.Valid {
+		res.Username = &user.Username.String
+	}
+	return ctx.JSON(http.StatusOK, res)
 }
 
 // Logout
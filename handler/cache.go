@@ -0,0 +1,24 @@
+package handler
+
+import (
+	"blog-backend/api"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Get read cache statistics
+// (GET /cache/stats)
+func (h *Handler) GetCacheStats(ctx echo.Context) error {
+	stats := h.Repo.CacheStats()
+	ttlSeconds := int(stats.TTL.Seconds())
+	return ctx.JSON(http.StatusOK, api.CacheStats{
+		Enabled:    &stats.Enabled,
+		Size:       &stats.Size,
+		Capacity:   &stats.Capacity,
+		TtlSeconds: &ttlSeconds,
+		Hits:       &stats.Hits,
+		Misses:     &stats.Misses,
+		Evictions:  &stats.Evictions,
+	})
+}
@@ -3,7 +3,9 @@ package handler
 import (
 	"blog-backend/api"
 	"blog-backend/model"
+	"encoding/json"
 	"net/http"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
@@ -27,7 +29,11 @@ func (h *Handler) GetCategories(ctx echo.Context) error {
 			Name: &category.Name,
 		})
 	}
-	return ctx.JSON(http.StatusOK, apiCategories)
+	body, err := json.Marshal(apiCategories)
+	if err != nil {
+		return ctx.JSON(http.StatusInternalServerError, err)
+	}
+	return respondWithValidators(ctx, body, weakContentETag(body), time.Time{}, taxonomyCacheControl)
 }
 
 // Create a new category
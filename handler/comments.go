@@ -6,26 +6,63 @@ import (
 	"blog-backend/model"
 	"database/sql"
 	"net/http"
+	"net/mail"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 )
 
+// comments per page (トップレベルのスレッドの数)
+const (
+	commentsPerPage    = 20
+	maxCommentsPerPage = 100
+)
+
 // Get comments for an article
 // (GET /comments)
 func (h *Handler) GetComments(ctx echo.Context, params api.GetCommentsParams) error {
+	articleId, err := uuid.Parse(params.ArticleId)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, err)
+	}
+	page := 1
+	if params.Page != nil && *params.Page > 1 {
+		page = *params.Page
+	}
+	perPage := commentsPerPage
+	if params.PerPage != nil && *params.PerPage > 0 {
+		perPage = min(*params.PerPage, maxCommentsPerPage)
+	}
+	sortBy := model.CommentSortOldest
+	if params.Sort != nil {
+		switch *params.Sort {
+		case api.Newest:
+			sortBy = model.CommentSortNewest
+		case api.Reactions:
+			sortBy = model.CommentSortReactions
+		}
+	}
 	// find comments by article id
-	comments, err := h.Repo.GetCommentsByArticle(ctx.Request().Context(), uuid.MustParse(params.ArticleId), nil)
+	result, err := h.Repo.GetCommentPage(ctx.Request().Context(), articleId, sortBy, page, perPage)
 	if err != nil {
-		logger.Println("GetCommentsByArticle Error: ", err)
+		logger.Println("GetCommentPage Error: ", err)
+		return ctx.JSON(http.StatusInternalServerError, err)
+	}
+	apiComments := convertCommentsToAPIComments(result.Comments, false)
+	if err := h.attachCommentReactions(ctx, apiComments); err != nil {
 		return ctx.JSON(http.StatusInternalServerError, err)
 	}
-	if len(comments) == 0 {
-		logger.Println("No comments found")
-		return ctx.JSON(http.StatusOK, []model.Comment{})
+	if params.Format != nil && *params.Format == api.Tree {
+		apiComments = buildAPICommentTree(apiComments)
 	}
-	return ctx.JSON(http.StatusOK, comments)
+	return ctx.JSON(http.StatusOK, api.CommentListResponse{
+		Comments: &apiComments,
+		Total:    &result.Total,
+		Threads:  &result.Threads,
+		Page:     &page,
+		PerPage:  &perPage,
+	})
 }
 
 // Post a comment
@@ -36,11 +73,25 @@ func (h *Handler) PostComments(ctx echo.Context) error {
 	if err := ctx.Bind(&req); err != nil {
 		return ctx.JSON(http.StatusBadRequest, err)
 	}
-	// UserIdが存在しない場合はIPアドレスでユーザーを特定する
-	userId := uuid.Nil
+	articleId, err := uuid.Parse(req.ArticleId)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, "invalid articleId")
+	}
+	// userIdは古いクライアントとの互換のために受け取るだけで、投稿者の特定には使わない
+	// 他人のIDを送れば初めての投稿者の保留を避けたり、名前を書き換えたりできてしまうため
 	if req.UserId != nil {
-		userId = uuid.MustParse(*req.UserId)
-	} else {
+		if _, err := uuid.Parse(*req.UserId); err != nil {
+			return ctx.JSON(http.StatusBadRequest, "invalid userId")
+		}
+	}
+	// 投稿者はログイン中のユーザー、なければIPアドレスで特定する
+	visitor, err := currentVisitor(ctx, h.Repo)
+	if err != nil {
+		logger.Println("currentVisitor Error: ", err)
+		return ctx.JSON(http.StatusInternalServerError, err)
+	}
+	userId := visitor.UUID
+	if !visitor.Valid {
 		userIdFromDB, err := h.Repo.CheckIPAddressAndReturnUserIDWithUserName(ctx.Request().Context(), ctx.RealIP(), req.Username)
 		if err != nil {
 			logger.Println("CheckIPAddressAndReturnUserIDWithUserName Error: ", err)
@@ -49,26 +100,81 @@ func (h *Handler) PostComments(ctx echo.Context) error {
 		userId = userIdFromDB
 	}
 	logger.Println("UserId: ", userId)
-	// add comment
-	err := h.Repo.CreateComment(ctx.Request().Context(), model.Comment{
+	// 返信の場合は返信先のコメント
+	parentId := uuid.NullUUID{}
+	if req.ParentId != nil {
+		id, err := uuid.Parse(*req.ParentId)
+		if err != nil {
+			return ctx.JSON(http.StatusBadRequest, err)
+		}
+		parentId = uuid.NullUUID{UUID: id, Valid: true}
+	}
+	// 返信の通知を希望する場合はメールアドレスが必要
+	notifyEmail := ""
+	if req.NotifyReplies != nil && *req.NotifyReplies {
+		if req.Email == nil {
+			return ctx.JSON(http.StatusBadRequest, "email is required to receive reply notifications")
+		}
+		address, err := mail.ParseAddress(string(*req.Email))
+		if err != nil {
+			return ctx.JSON(http.StatusBadRequest, "invalid email")
+		}
+		notifyEmail = address.Address
+	}
+	comment := model.Comment{
 		ID:        uuid.New(),
-		ArticleID: uuid.MustParse(req.ArticleId),
+		ArticleID: articleId,
 		AuthorID:  userId,
 		Content:   req.Content,
 		CreatedAt: time.Now(),
 		Author:    sql.NullString{String: req.Username, Valid: req.Username != ""},
-	})
+		ParentID:  parentId,
+	}
+	// スパムらしいコメントと、初めての投稿者、URL、禁止語を含むコメントは承認待ちにする
+	spam := model.SpamInput{
+		IPAddress:      ctx.RealIP(),
+		FormRenderedAt: req.FormRenderedAt,
+		SubmittedAt:    time.Now(),
+	}
+	if req.Website != nil {
+		spam.Honeypot = *req.Website
+	}
+	if err := h.Repo.ModerateComment(ctx.Request().Context(), &comment, model.ModerationRulesFromEnv(), spam); err != nil {
+		logger.Println("ModerateComment Error: ", err)
+		return ctx.JSON(http.StatusInternalServerError, err)
+	}
+	// add comment
+	err = h.Repo.CreateComment(ctx.Request().Context(), comment)
+	if err == model.ErrParentCommentNotFound || err == model.ErrCommentTooDeep {
+		return ctx.JSON(http.StatusBadRequest, err.Error())
+	}
 	if err != nil {
 		logger.Println("CreateComment Error: ", err)
 		return ctx.JSON(http.StatusInternalServerError, err)
 	}
+	if notifyEmail != "" {
+		// コメントは作成済みなので、購読に失敗してもコメントの投稿は成功として返す
+		if err := h.Repo.SubscribeToReplies(ctx.Request().Context(), comment.ID, notifyEmail); err != nil {
+			logger.Println("SubscribeToReplies Error: ", err)
+		}
+	}
+	if comment.Status != model.CommentStatusApproved {
+		return ctx.JSON(http.StatusAccepted, "Comment is awaiting moderation")
+	}
 	return ctx.JSON(http.StatusCreated, "Comment created")
 }
 
 // Delete a comment
 // (DELETE /comments/{id})
 func (h *Handler) DeleteCommentsId(ctx echo.Context, id string) error {
-	err := h.Repo.DeleteComment(ctx.Request().Context(), uuid.MustParse(id))
+	comment, err := h.getCommentForChange(ctx, id)
+	if err != nil || comment == nil {
+		return err
+	}
+	if _, ok, err := h.authorizeCommentChange(ctx, *comment, false); !ok {
+		return err
+	}
+	err = h.Repo.DeleteComment(ctx.Request().Context(), comment.ID)
 	if err != nil {
 		return ctx.JSON(http.StatusInternalServerError, err)
 	}
@@ -82,13 +188,134 @@ func (h *Handler) PatchCommentsId(ctx echo.Context, id string) error {
 	if err := ctx.Bind(&req); err != nil {
 		return ctx.JSON(http.StatusBadRequest, err)
 	}
-	// update comment
-	err := h.Repo.UpdateComment(ctx.Request().Context(), model.Comment{
-		ID:      uuid.MustParse(id),
-		Content: *req.Content,
-	})
+	if req.Content == nil {
+		return ctx.JSON(http.StatusBadRequest, "content is required")
+	}
+	comment, err := h.getCommentForChange(ctx, id)
+	if err != nil || comment == nil {
+		return err
+	}
+	editorId, ok, err := h.authorizeCommentChange(ctx, *comment, true)
+	if !ok {
+		return err
+	}
+	if *req.Content == comment.Content {
+		return ctx.JSON(http.StatusOK, "Comment updated")
+	}
+	comment.Content = *req.Content
+	// 公開済みのコメントは編集後の本文もスパム判定とモデレーションのルールに通し、通らなければ承認待ちに戻す
+	// 承認待ちや却下のコメントは状態を変えず、管理者の判断を待つ
+	if comment.Status == model.CommentStatusApproved {
+		spam := model.SpamInput{
+			IPAddress:   ctx.RealIP(),
+			SubmittedAt: time.Now(),
+		}
+		if err := h.Repo.ModerateComment(ctx.Request().Context(), comment, model.ModerationRulesFromEnv(), spam); err != nil {
+			logger.Println("ModerateComment Error: ", err)
+			return ctx.JSON(http.StatusInternalServerError, err)
+		}
+		if comment.Status != model.CommentStatusApproved {
+			comment.Status = model.CommentStatusPending
+		}
+	}
+	// update comment (以前の本文は履歴に残る)
+	err = h.Repo.UpdateComment(ctx.Request().Context(), *comment, uuid.NullUUID{UUID: editorId, Valid: true})
 	if err != nil {
 		return ctx.JSON(http.StatusInternalServerError, err)
 	}
+	if comment.Status != model.CommentStatusApproved {
+		return ctx.JSON(http.StatusAccepted, "Comment is awaiting moderation")
+	}
 	return ctx.JSON(http.StatusOK, "Comment updated")
 }
+
+// getCommentForChange - 変更するコメントを取得する。見つからない場合はレスポンスを書き込んでnilを返す
+func (h *Handler) getCommentForChange(ctx echo.Context, id string) (*model.Comment, error) {
+	commentId, err := uuid.Parse(id)
+	if err != nil {
+		return nil, ctx.JSON(http.StatusBadRequest, err)
+	}
+	comment, err := h.Repo.GetCommentByIDAnyStatus(ctx.Request().Context(), commentId)
+	if err == sql.ErrNoRows {
+		return nil, ctx.JSON(http.StatusNotFound, "Comment not found")
+	}
+	if err != nil {
+		logger.Println("GetCommentByIDAnyStatus Error: ", err)
+		return nil, ctx.JSON(http.StatusInternalServerError, err)
+	}
+	return &comment, nil
+}
+
+// authorizeCommentChange - コメントを変更できるのは管理者と投稿者本人(ログイン中のユーザーか、同じIPアドレスの訪問者)だけ
+// 編集の場合、投稿者はCommentEditWindowの間だけ変更できる。変更できる場合は変更するユーザーのIDを返す
+// 変更できない場合は403を書き込んでfalseを返す
+func (h *Handler) authorizeCommentChange(ctx echo.Context, comment model.Comment, edit bool) (uuid.UUID, bool, error) {
+	user, err := h.authenticatedUser(ctx)
+	if err != nil {
+		logger.Println("authenticatedUser Error: ", err)
+		return uuid.Nil, false, ctx.JSON(http.StatusInternalServerError, err)
+	}
+	if user != nil && user.IsAdmin {
+		return user.ID, true, nil
+	}
+	owner := user != nil && user.ID == comment.AuthorID
+	if !owner {
+		visitor, err := h.Repo.GetUserByIpAddress(ctx.Request().Context(), ctx.RealIP())
+		if err != nil && err != sql.ErrNoRows {
+			logger.Println("GetUserByIpAddress Error: ", err)
+			return uuid.Nil, false, ctx.JSON(http.StatusInternalServerError, err)
+		}
+		owner = err == nil && visitor.ID == comment.AuthorID
+	}
+	if !owner {
+		return uuid.Nil, false, forbidden(ctx, "Only the author or an admin can change this comment")
+	}
+	if edit && time.Since(comment.CreatedAt) > model.CommentEditWindow() {
+		return uuid.Nil, false, forbidden(ctx, "The edit window for this comment has passed")
+	}
+	return comment.AuthorID, true, nil
+}
+
+// Get previous versions of a comment
+// (GET /comments/{id}/revisions)
+func (h *Handler) GetCommentsIdRevisions(ctx echo.Context, id string) error {
+	user, err := h.authenticatedUser(ctx)
+	if err != nil {
+		logger.Println("authenticatedUser Error: ", err)
+		return ctx.JSON(http.StatusInternalServerError, err)
+	}
+	if user == nil || !user.IsAdmin {
+		return forbidden(ctx, "Only admins can view comment history")
+	}
+	comment, err := h.getCommentForChange(ctx, id)
+	if err != nil || comment == nil {
+		return err
+	}
+	revisions, err := h.Repo.GetCommentRevisions(ctx.Request().Context(), comment.ID)
+	if err != nil {
+		logger.Println("GetCommentRevisions Error: ", err)
+		return ctx.JSON(http.StatusInternalServerError, err)
+	}
+	return ctx.JSON(http.StatusOK, convertCommentRevisionsToAPICommentRevisions(revisions))
+}
+
+// Stop reply notifications for a comment
+// (GET /comments/subscriptions/{id}/unsubscribe)
+func (h *Handler) GetCommentsSubscriptionsIdUnsubscribe(ctx echo.Context, id string, params api.GetCommentsSubscriptionsIdUnsubscribeParams) error {
+	subscriptionId, err := uuid.Parse(id)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, err)
+	}
+	if !model.ValidUnsubscribeToken(subscriptionId, params.Token) {
+		return forbidden(ctx, "Invalid unsubscribe token")
+	}
+	err = h.Repo.Unsubscribe(ctx.Request().Context(), subscriptionId)
+	if err == sql.ErrNoRows {
+		return ctx.JSON(http.StatusNotFound, "Subscription not found")
+	}
+	if err != nil {
+		logger.Println("Unsubscribe Error: ", err)
+		return ctx.JSON(http.StatusInternalServerError, err)
+	}
+	return ctx.JSON(http.StatusOK, "Unsubscribed")
+}
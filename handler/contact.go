@@ -1,8 +1,11 @@
 package handler
 
 import (
+	"database/sql"
 	"net/http"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 
 	"blog-backend/api"
@@ -23,11 +26,62 @@ func (h *Handler) PostContact(ctx echo.Context) error {
 		return ctx.JSON(http.StatusBadRequest, "Name, Email, and Message are required")
 	}
 
+	// スパムを判定して記録する。スパムの場合もボットに判定を知らせないよう、同じレスポンスを返す
+	config := model.SpamConfigFromEnv()
+	spam := model.SpamInput{
+		Content:        *req.Message,
+		Name:           *req.Name,
+		Email:          string(*req.Email),
+		IPAddress:      ctx.RealIP(),
+		FormRenderedAt: req.FormRenderedAt,
+		SubmittedAt:    time.Now(),
+	}
+	if req.Website != nil {
+		spam.Honeypot = *req.Website
+	}
+	result, err := h.Repo.ScoreSpam(ctx.Request().Context(), spam, config)
+	if err != nil {
+		logger.Println("ScoreSpam Error: ", err)
+		return ctx.JSON(http.StatusInternalServerError, err)
+	}
+	message := model.ContactMessage{
+		ID:          uuid.New(),
+		Name:        *req.Name,
+		Email:       string(*req.Email),
+		Message:     *req.Message,
+		IpAddress:   sql.NullString{String: ctx.RealIP(), Valid: true},
+		SpamScore:   result.Score,
+		SpamReasons: sql.NullString{String: result.Reason(), Valid: len(result.Reasons) > 0},
+		IsSpam:      result.Score >= config.SpamThreshold,
+		CreatedAt:   time.Now(),
+	}
+	if err := h.Repo.CreateContactMessage(ctx.Request().Context(), message); err != nil {
+		logger.Println("CreateContactMessage Error: ", err)
+		return ctx.JSON(http.StatusInternalServerError, err)
+	}
+	if message.IsSpam {
+		return ctx.JSON(http.StatusOK, "Message sent successfully")
+	}
+
 	// send Slack to admin
-	err := model.SendSlack(ctx.Request().Context(), *req.Name, string(*req.Email), *req.Message)
+	err = model.SendSlack(ctx.Request().Context(), *req.Name, string(*req.Email), *req.Message)
 	if err != nil {
 		logger.Println("Failed to send message to Slack: ", err)
 		return ctx.JSON(http.StatusInternalServerError, err)
 	}
 	return ctx.JSON(http.StatusOK, "Message sent successfully")
 }
+
+// Get contact messages
+// (GET /contact/messages)
+func (h *Handler) GetContactMessages(ctx echo.Context, params api.GetContactMessagesParams) error {
+	if ok, err := h.requireAdmin(ctx, "Only admins can read contact messages"); !ok {
+		return err
+	}
+	messages, err := h.Repo.GetContactMessages(ctx.Request().Context(), params.Spam)
+	if err != nil {
+		logger.Println("GetContactMessages Error: ", err)
+		return ctx.JSON(http.StatusInternalServerError, err)
+	}
+	return ctx.JSON(http.StatusOK, convertContactMessagesToAPIContactMessages(messages))
+}
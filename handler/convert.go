@@ -18,6 +18,13 @@ func convertNullStringToStringPoint(s sql.NullString) *string {
 	return nil
 }
 
+func convertStringPointToNullString(s *string) sql.NullString {
+	if s != nil {
+		return sql.NullString{String: *s, Valid: true}
+	}
+	return sql.NullString{}
+}
+
 func convertNullInt64ToIntPoint(i sql.NullInt64, defaltValue *int) *int {
 	if i.Valid {
 		val := int(i.Int64)
@@ -28,43 +35,61 @@ func convertNullInt64ToIntPoint(i sql.NullInt64, defaltValue *int) *int {
 
 func convertArticlesToAPIArticles(ctx echo.Context, articles []model.Article, repo *model.Repository) ([]api.Article, error) {
 	var returnArticles []api.Article
-	authorNameIdMap := make(map[uuid.UUID]string)
-	catergoryNameIdMap := make(map[uuid.UUID]api.Category)
+	// 著者・カテゴリー・タグ・いいね数などは一覧の分をまとめて取得する
+	relations, err := repo.LoadArticleRelations(ctx.Request().Context(), articles)
+	if err != nil {
+		logger.Println("error loading article relations", err)
+		return nil, err
+	}
+	// リアクションは訪問者ごとに違うのでキャッシュせずに取得する
+	visitor, err := currentVisitor(ctx, repo)
+	if err != nil {
+		logger.Println("error resolving visitor", err)
+		return nil, err
+	}
+	articleIDs := make([]uuid.UUID, 0, len(articles))
+	for _, article := range articles {
+		articleIDs = append(articleIDs, article.ID)
+	}
+	reactions, err := repo.GetReactionSummaries(ctx.Request().Context(), model.ReactionTargetArticle, articleIDs, visitor)
+	if err != nil {
+		logger.Println("error loading article reactions", err)
+		return nil, err
+	}
+	likeStatuses, err := repo.GetLikeStatuses(ctx.Request().Context(), articleIDs, visitor)
+	if err != nil {
+		logger.Println("error loading like statuses", err)
+		return nil, err
+	}
 	for _, article := range articles {
 
 		id := article.ID.String()
 
-		if _, ok := authorNameIdMap[article.AuthorID]; !ok {
-			author, err := repo.GetUserNameById(ctx.Request().Context(), article.AuthorID)
-			if err != nil {
-				logger.Println("error getting author name", err)
-				return nil, err
-			}
-			authorNameIdMap[article.AuthorID] = author.Username.String
-		}
-		author := authorNameIdMap[article.AuthorID]
+		// langパラメータ、Accept-Language、デフォルト言語の順に表示する言語を決める
+		translations := relations.Translations[article.ID]
+		availableLanguages := model.AvailableLanguages(article, translations)
+		lang := model.NegotiateLanguage(availableLanguages, ctx.QueryParam("lang"), ctx.Request().Header.Get("Accept-Language"))
+		var translation *model.ArticleTranslation
+		article, translation = model.LocalizeArticle(article, translations, lang)
 
-		if _, ok := catergoryNameIdMap[article.CategoryID]; !ok {
-			category, err := repo.GetCategoryNameByID(ctx.Request().Context(), article.CategoryID)
-			if err != nil {
-				logger.Println("error getting category name", err)
-				return nil, err
-			}
-			category_id := category.ID.String()
-			catergoryNameIdMap[article.CategoryID] = api.Category{
-				Id:   &category_id,
-				Name: &category.Name,
-			}
+		author := relations.AuthorNames[article.AuthorID]
+
+		categoryModel := relations.Categories[article.CategoryID]
+		category_id := categoryModel.ID.String()
+		category := api.Category{
+			Id:   &category_id,
+			Name: &categoryModel.Name,
 		}
-		category := catergoryNameIdMap[article.CategoryID]
 
-		tags, err := repo.GetTagsByArticle(ctx.Request().Context(), article.ID, nil)
-		if err != nil {
-			logger.Println("error getting tags", err)
-			return nil, err
+		var slug, excerpt *string
+		if translation != nil {
+			slug = &translation.Slug
+			excerpt = convertNullStringToStringPoint(translation.Excerpt)
 		}
+		alternates := convertTranslationsToAPIAlternates(article.ID, availableLanguages, translations)
+
 		var tagList []api.Tag
-		for _, tag := range tags {
+		for _, tag := range relations.Tags[article.ID] {
 			tag_id := tag.ID.String()
 			tagList = append(tagList, api.Tag{
 				Id:   &tag_id,
@@ -72,28 +97,38 @@ func convertArticlesToAPIArticles(ctx echo.Context, articles []model.Article, re
 			})
 		}
 
-		likeCount, err := repo.GetLikesCountByArticle(ctx.Request().Context(), article.ID)
-		if err != nil {
-			logger.Println("error getting like count", err)
-			return nil, err
-		}
+		likeCount := article.LikeCount
+		liked := likeStatuses[article.ID].Liked
+		commentCount := article.CommentCount
+		seriesNavigation := relations.Series[article.ID]
 
 		zeroViewCount := 0
+		version := article.Version
 
 		authorIdStr := article.AuthorID.String()
+		apiReactions := convertReactionSummariesToAPIReactionSummaries(reactions[article.ID])
 		returnArticles = append(returnArticles, api.Article{
-			Author:    &author,
-			AuthorId:  &authorIdStr,
-			Category:  &category,
-			Content:   &article.Content,
-			ImageUrl:  convertNullStringToStringPoint(article.ImageURL),
-			ViewCount: convertNullInt64ToIntPoint(article.ViewCount, &zeroViewCount),
-			CreatedAt: &article.CreatedAt,
-			Id:        &id,
-			LikeCount: &likeCount,
-			Tags:      &tagList,
-			Title:     &article.Title,
-			UpdatedAt: &article.UpdatedAt,
+			Alternates:   &alternates,
+			Author:       &author,
+			AuthorId:     &authorIdStr,
+			Category:     &category,
+			CommentCount: &commentCount,
+			Content:      &article.Content,
+			ImageUrl:     convertNullStringToStringPoint(article.ImageURL),
+			ViewCount:    convertNullInt64ToIntPoint(article.ViewCount, &zeroViewCount),
+			CreatedAt:    &article.CreatedAt,
+			Excerpt:      excerpt,
+			Id:           &id,
+			Lang:         &article.Lang,
+			LikeCount:    &likeCount,
+			Liked:        &liked,
+			Reactions:    &apiReactions,
+			Series:       convertSeriesNavigationToAPIArticleSeries(seriesNavigation),
+			Slug:         slug,
+			Tags:         &tagList,
+			Title:        &article.Title,
+			UpdatedAt:    &article.UpdatedAt,
+			Version:      &version,
 		})
 	}
 	// CreatedAtの降順にソート
@@ -102,3 +137,266 @@ func convertArticlesToAPIArticles(ctx echo.Context, articles []model.Article, re
 	})
 	return returnArticles, nil
 }
+
+func convertSeriesNavigationToAPIArticleSeries(nav *model.SeriesNavigation) *api.ArticleSeries {
+	if nav == nil {
+		return nil
+	}
+	id := nav.SeriesID.String()
+	articleSeries := &api.ArticleSeries{
+		Id:    &id,
+		Title: &nav.SeriesTitle,
+		Part:  &nav.Part,
+		Total: &nav.Total,
+	}
+	if nav.PrevArticleID != nil {
+		prev := nav.PrevArticleID.String()
+		articleSeries.PrevArticleId = &prev
+	}
+	if nav.NextArticleID != nil {
+		next := nav.NextArticleID.String()
+		articleSeries.NextArticleId = &next
+	}
+	return articleSeries
+}
+
+func convertSeriesToAPISeries(series model.Series, articleIDs []uuid.UUID, articles []api.Article) api.Series {
+	id := series.ID.String()
+	ids := make([]string, 0, len(articleIDs))
+	for _, articleID := range articleIDs {
+		ids = append(ids, articleID.String())
+	}
+	apiSeries := api.Series{
+		Id:          &id,
+		Title:       &series.Title,
+		Description: convertNullStringToStringPoint(series.Description),
+		ArticleIds:  &ids,
+		CreatedAt:   &series.CreatedAt,
+		UpdatedAt:   &series.UpdatedAt,
+	}
+	if articles != nil {
+		apiSeries.Articles = &articles
+	}
+	return apiSeries
+}
+
+// sortAPIArticles - orderbyの値(created_at, view_count, like_count, comment_count)とorder(asc, desc)で並べ替える
+func sortAPIArticles(articles []api.Article, orderby string, order string) {
+	value := func(article api.Article) int64 {
+		switch orderby {
+		case "view_count":
+			if article.ViewCount != nil {
+				return int64(*article.ViewCount)
+			}
+		case "like_count":
+			if article.LikeCount != nil {
+				return int64(*article.LikeCount)
+			}
+		case "comment_count":
+			if article.CommentCount != nil {
+				return int64(*article.CommentCount)
+			}
+		default:
+			return article.CreatedAt.UnixNano()
+		}
+		return 0
+	}
+	sort.SliceStable(articles, func(i, j int) bool {
+		if order == "asc" {
+			return value(articles[i]) < value(articles[j])
+		}
+		return value(articles[i]) > value(articles[j])
+	})
+}
+
+// sortAPIArticlesBySeriesPart - シリーズ内の順番(part)の昇順にソート
+func sortAPIArticlesBySeriesPart(articles []api.Article) {
+	sort.SliceStable(articles, func(i, j int) bool {
+		if articles[i].Series == nil || articles[j].Series == nil {
+			return false
+		}
+		return *articles[i].Series.Part < *articles[j].Series.Part
+	})
+}
+
+// convertTranslationsToAPIAlternates - 記事を読めるすべての言語をhreflangの代替リンクとして返す
+func convertTranslationsToAPIAlternates(articleID uuid.UUID, availableLanguages []string, translations []model.ArticleTranslation) []api.ArticleAlternate {
+	alternates := make([]api.ArticleAlternate, 0, len(availableLanguages))
+	for _, lang := range availableLanguages {
+		hreflang := lang
+		href := model.ArticlePageLink(articleID, lang)
+		alternate := api.ArticleAlternate{
+			Hreflang: &hreflang,
+			Href:     &href,
+		}
+		for _, t := range translations {
+			if t.Lang == lang {
+				slug := t.Slug
+				alternate.Slug = &slug
+			}
+		}
+		alternates = append(alternates, alternate)
+	}
+	return alternates
+}
+
+func convertTranslationToAPITranslation(translation model.ArticleTranslation) api.ArticleTranslation {
+	return api.ArticleTranslation{
+		Lang:      &translation.Lang,
+		Title:     &translation.Title,
+		Content:   &translation.Content,
+		Excerpt:   convertNullStringToStringPoint(translation.Excerpt),
+		Slug:      &translation.Slug,
+		CreatedAt: &translation.CreatedAt,
+		UpdatedAt: &translation.UpdatedAt,
+	}
+}
+
+func convertTrashItemsToAPITrashItems(items []model.TrashItem) []api.TrashItem {
+	apiItems := make([]api.TrashItem, 0, len(items))
+	for _, item := range items {
+		id := item.ID.String()
+		label := item.Label
+		deletedAt := item.DeletedAt
+		purgeAt := item.DeletedAt.Add(model.TrashRetention())
+		apiItems = append(apiItems, api.TrashItem{
+			Id:        &id,
+			Label:     &label,
+			DeletedAt: &deletedAt,
+			PurgeAt:   &purgeAt,
+		})
+	}
+	return apiItems
+}
+
+// convertCommentsToAPIComments - moderationがtrueのときだけ、管理者向けの審査の状態と理由、スパムスコアを含める
+func convertCommentsToAPIComments(comments []model.Comment, moderation bool) []api.Comment {
+	apiComments := make([]api.Comment, 0, len(comments))
+	for _, comment := range comments {
+		id := comment.ID.String()
+		articleID := comment.ArticleID.String()
+		content := comment.Content
+		contentHTML := comment.HTML()
+		createdAt := comment.CreatedAt
+		depth := comment.Depth
+		replyCount := comment.ReplyCount
+		reactionCount := comment.ReactionCount
+		deleted := comment.DeletedAt.Valid
+		apiComment := api.Comment{
+			Id:            &id,
+			ArticleId:     &articleID,
+			Author:        convertNullStringToStringPoint(comment.Author),
+			Content:       &content,
+			ContentHtml:   &contentHTML,
+			CreatedAt:     &createdAt,
+			Depth:         &depth,
+			ReplyCount:    &replyCount,
+			ReactionCount: &reactionCount,
+			Deleted:       &deleted,
+		}
+		if moderation {
+			status := api.CommentStatus(comment.Status)
+			apiComment.Status = &status
+			apiComment.ModerationReason = convertNullStringToStringPoint(comment.ModerationReason)
+			if comment.SpamScore.Valid {
+				spamScore := comment.SpamScore.Float64
+				apiComment.SpamScore = &spamScore
+			}
+		}
+		edited := comment.EditedAt.Valid
+		apiComment.Edited = &edited
+		if comment.EditedAt.Valid {
+			editedAt := comment.EditedAt.Time
+			apiComment.EditedAt = &editedAt
+		}
+		if comment.ParentID.Valid {
+			parentID := comment.ParentID.UUID.String()
+			apiComment.ParentId = &parentID
+		}
+		apiComments = append(apiComments, apiComment)
+	}
+	return apiComments
+}
+
+func convertReactionSummariesToAPIReactionSummaries(summaries []model.ReactionSummary) []api.ReactionSummary {
+	apiSummaries := make([]api.ReactionSummary, 0, len(summaries))
+	for _, summary := range summaries {
+		emoji := summary.Emoji
+		count := summary.Count
+		reacted := summary.Reacted
+		apiSummaries = append(apiSummaries, api.ReactionSummary{
+			Emoji:   &emoji,
+			Count:   &count,
+			Reacted: &reacted,
+		})
+	}
+	return apiSummaries
+}
+
+// buildAPICommentTree - スレッドの順に並んだコメントを、トップレベルのコメントの下に返信を入れ子にした形にする
+func buildAPICommentTree(comments []api.Comment) []api.Comment {
+	children := make(map[string][]api.Comment)
+	roots := make([]api.Comment, 0)
+	for _, comment := range comments {
+		if comment.ParentId != nil {
+			children[*comment.ParentId] = append(children[*comment.ParentId], comment)
+		} else {
+			roots = append(roots, comment)
+		}
+	}
+	var attach func(list []api.Comment) []api.Comment
+	attach = func(list []api.Comment) []api.Comment {
+		for i := range list {
+			replies := attach(children[*list[i].Id])
+			list[i].Replies = &replies
+		}
+		return list
+	}
+	return attach(roots)
+}
+
+func convertCommentRevisionsToAPICommentRevisions(revisions []model.CommentRevision) []api.CommentRevision {
+	apiRevisions := make([]api.CommentRevision, 0, len(revisions))
+	for _, revision := range revisions {
+		id := revision.ID.String()
+		commentID := revision.CommentID.String()
+		content := revision.Content
+		replacedAt := revision.ReplacedAt
+		apiRevision := api.CommentRevision{
+			Id:         &id,
+			CommentId:  &commentID,
+			Content:    &content,
+			ReplacedAt: &replacedAt,
+		}
+		if revision.EditedBy.Valid {
+			editedBy := revision.EditedBy.UUID.String()
+			apiRevision.EditedBy = &editedBy
+		}
+		apiRevisions = append(apiRevisions, apiRevision)
+	}
+	return apiRevisions
+}
+
+func convertContactMessagesToAPIContactMessages(messages []model.ContactMessage) []api.ContactMessage {
+	apiMessages := make([]api.ContactMessage, 0, len(messages))
+	for _, message := range messages {
+		id := message.ID.String()
+		name := message.Name
+		email := message.Email
+		body := message.Message
+		spamScore := message.SpamScore
+		isSpam := message.IsSpam
+		createdAt := message.CreatedAt
+		apiMessages = append(apiMessages, api.ContactMessage{
+			Id:          &id,
+			Name:        &name,
+			Email:       &email,
+			Message:     &body,
+			SpamScore:   &spamScore,
+			SpamReasons: convertNullStringToStringPoint(message.SpamReasons),
+			IsSpam:      &isSpam,
+			CreatedAt:   &createdAt,
+		})
+	}
+	return apiMessages
+}
@@ -10,12 +10,14 @@ type Handler struct {
 	Repo         *model.Repository
 	Config       *model.Configuration
 	DriveService *drive.Service
+	Views        *model.ViewWriter
 }
 
-func New(repo *model.Repository, config *model.Configuration, srv *drive.Service) *Handler {
+func New(repo *model.Repository, config *model.Configuration, srv *drive.Service, views *model.ViewWriter) *Handler {
 	return &Handler{
 		Repo:         repo,
 		Config:       config,
 		DriveService: srv,
+		Views:        views,
 	}
 }
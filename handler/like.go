@@ -1,15 +1,14 @@
 package handler
 
 import (
+	"database/sql"
 	"net/http"
-	"time"
 
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 
 	"blog-backend/api"
 	"blog-backend/logger"
-	"blog-backend/model"
 )
 
 // Add a like to an article
@@ -19,27 +18,22 @@ func (h *Handler) PostLikes(ctx echo.Context) error {
 	if err := ctx.Bind(&req); err != nil {
 		return ctx.JSON(http.StatusBadRequest, "invalid request")
 	}
-	// user idが存在しない場合はIPアドレスでユーザーを特定する
-	userID := uuid.Nil
-	if req.UserId != nil {
-		userID = uuid.MustParse(*req.UserId)
-	} else {
-		userIDFromDB, err := h.Repo.CheckIPAddressAndReturnUserID(ctx.Request().Context(), ctx.RealIP())
-		if err != nil {
-			logger.Println("CheckIPAddressAndReturnUserID Error: ", err)
-			return ctx.JSON(http.StatusInternalServerError, err)
-		}
-		userID = userIDFromDB
-	}
-	// add like
-	err := h.Repo.CreateLike(ctx.Request().Context(), model.Like{
-		ID:        uuid.New(),
-		ArticleID: uuid.MustParse(req.ArticleId),
-		UserID:    userID,
-		CreatedAt: time.Now(),
-	})
+	articleID, err := uuid.Parse(req.ArticleId)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, err)
+	}
+	visitor, ok, err := h.likeVisitor(ctx, true)
+	if !ok {
+		return err
+	}
+	userID := visitor.UUID
+	// add like。すでにいいねしている場合は何もしない
+	_, err = h.Repo.SetLike(ctx.Request().Context(), articleID, userID, true)
+	if err == sql.ErrNoRows {
+		return ctx.JSON(http.StatusNotFound, "Article not found")
+	}
 	if err != nil {
-		logger.Println("CreateLike Error: ", err)
+		logger.Println("SetLike Error: ", err)
 		return ctx.JSON(http.StatusInternalServerError, err)
 	}
 	return ctx.JSON(http.StatusCreated, "like added")
@@ -48,30 +42,161 @@ func (h *Handler) PostLikes(ctx echo.Context) error {
 // Get likes for an article
 // (GET /likes)
 func (h *Handler) GetLikes(ctx echo.Context, params api.GetLikesParams) error {
+	articleID, err := uuid.Parse(params.ArticleId)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, err)
+	}
 	// find likes by article id
-	likes, err := h.Repo.GetLikesByArticle(ctx.Request().Context(), uuid.MustParse(params.ArticleId), nil)
+	likes, err := h.Repo.GetLikesByArticle(ctx.Request().Context(), articleID, nil)
 	if err != nil {
 		return ctx.JSON(http.StatusInternalServerError, err)
 	}
-	// CheckIPAddressAndReturnUserID and check if the user has liked the article
-	userID, err := h.Repo.CheckIPAddressAndReturnUserID(ctx.Request().Context(), ctx.RealIP())
+	// 表示するだけでユーザーを作らないよう、記録のない訪問者はいいねしていないものとして扱う
+	visitor, err := currentVisitor(ctx, h.Repo)
 	if err != nil {
-		logger.Println("CheckIPAddressAndReturnUserID Error: ", err)
+		logger.Println("currentVisitor Error: ", err)
 		return ctx.JSON(http.StatusInternalServerError, err)
 	}
 	liked := false
 	for _, like := range likes {
-		if like.UserID == userID {
+		if visitor.Valid && like.UserID == visitor.UUID {
 			liked = true
 		}
 	}
 	likeCount := len(likes)
-	userIDStr := userID.String()
 	return ctx.JSON(http.StatusOK, api.LikeReturn{
 		ArticleId: &params.ArticleId,
 		LikeCount: &likeCount,
 		Liked:     &liked,
-		UserId:    &userIDStr,
+		UserId:    visitorIDString(visitor),
 	})
 
 }
+
+// 一度に問い合わせられる記事の数
+const maxLikeStatusArticles = 100
+
+// Get like counts and states for several articles
+// (GET /likes/status)
+func (h *Handler) GetLikesStatus(ctx echo.Context, params api.GetLikesStatusParams) error {
+	if len(params.ArticleIds) > maxLikeStatusArticles {
+		return ctx.JSON(http.StatusBadRequest, "too many articleIds")
+	}
+	articleIDs := make([]uuid.UUID, 0, len(params.ArticleIds))
+	for _, id := range params.ArticleIds {
+		articleID, err := uuid.Parse(id)
+		if err != nil {
+			return ctx.JSON(http.StatusBadRequest, err)
+		}
+		articleIDs = append(articleIDs, articleID)
+	}
+	// 一覧を表示するたびにユーザーを作らないよう、記録のない訪問者はいいねしていないものとして扱う
+	visitor, err := currentVisitor(ctx, h.Repo)
+	if err != nil {
+		logger.Println("currentVisitor Error: ", err)
+		return ctx.JSON(http.StatusInternalServerError, err)
+	}
+	statuses, err := h.Repo.GetLikeStatuses(ctx.Request().Context(), articleIDs, visitor)
+	if err != nil {
+		logger.Println("GetLikeStatuses Error: ", err)
+		return ctx.JSON(http.StatusInternalServerError, err)
+	}
+	userIDStr := visitorIDString(visitor)
+	likes := make([]api.LikeReturn, 0, len(articleIDs))
+	for _, articleID := range articleIDs {
+		status, ok := statuses[articleID]
+		if !ok {
+			continue
+		}
+		articleIDStr := articleID.String()
+		likeCount := status.LikeCount
+		liked := status.Liked
+		likes = append(likes, api.LikeReturn{
+			ArticleId: &articleIDStr,
+			LikeCount: &likeCount,
+			Liked:     &liked,
+			UserId:    userIDStr,
+		})
+	}
+	return ctx.JSON(http.StatusOK, likes)
+}
+
+// Set whether the visitor likes an article
+// (PUT /likes)
+func (h *Handler) PutLikes(ctx echo.Context) error {
+	var req api.PutLikesJSONRequestBody
+	if err := ctx.Bind(&req); err != nil {
+		return ctx.JSON(http.StatusBadRequest, "invalid request")
+	}
+	articleID, err := uuid.Parse(req.ArticleId)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, err)
+	}
+	visitor, ok, err := h.likeVisitor(ctx, req.Liked)
+	if !ok {
+		return err
+	}
+	return h.setLike(ctx, articleID, visitor, req.Liked)
+}
+
+// Remove a like from an article
+// (DELETE /likes)
+func (h *Handler) DeleteLikes(ctx echo.Context, params api.DeleteLikesParams) error {
+	articleID, err := uuid.Parse(params.ArticleId)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, err)
+	}
+	visitor, ok, err := h.likeVisitor(ctx, false)
+	if !ok {
+		return err
+	}
+	return h.setLike(ctx, articleID, visitor, false)
+}
+
+// likeVisitor - ログイン中のユーザー、なければIPアドレスでいいねする訪問者を特定する
+// いいねを外すだけの場合はユーザーを作らず、記録のない訪問者には無効なIDを返す
+func (h *Handler) likeVisitor(ctx echo.Context, create bool) (uuid.NullUUID, bool, error) {
+	visitor, err := currentVisitor(ctx, h.Repo)
+	if err != nil {
+		logger.Println("currentVisitor Error: ", err)
+		return uuid.NullUUID{}, false, ctx.JSON(http.StatusInternalServerError, err)
+	}
+	if visitor.Valid || !create {
+		return visitor, true, nil
+	}
+	userID, err := h.Repo.CheckIPAddressAndReturnUserID(ctx.Request().Context(), ctx.RealIP())
+	if err != nil {
+		logger.Println("CheckIPAddressAndReturnUserID Error: ", err)
+		return uuid.NullUUID{}, false, ctx.JSON(http.StatusInternalServerError, err)
+	}
+	return uuid.NullUUID{UUID: userID, Valid: true}, true, nil
+}
+
+// visitorIDString - 記録のある訪問者のIDを文字列で返す
+func visitorIDString(visitor uuid.NullUUID) *string {
+	if !visitor.Valid {
+		return nil
+	}
+	id := visitor.UUID.String()
+	return &id
+}
+
+// setLike - いいねをlikedの状態にし、新しいいいね数と状態を返す
+// 記録のない訪問者にはいいねがないので、いいね数を数え直すだけになる
+func (h *Handler) setLike(ctx echo.Context, articleID uuid.UUID, visitor uuid.NullUUID, liked bool) error {
+	likeCount, err := h.Repo.SetLike(ctx.Request().Context(), articleID, visitor.UUID, liked)
+	if err == sql.ErrNoRows {
+		return ctx.JSON(http.StatusNotFound, "Article not found")
+	}
+	if err != nil {
+		logger.Println("SetLike Error: ", err)
+		return ctx.JSON(http.StatusInternalServerError, err)
+	}
+	articleIDStr := articleID.String()
+	return ctx.JSON(http.StatusOK, api.LikeReturn{
+		ArticleId: &articleIDStr,
+		LikeCount: &likeCount,
+		Liked:     &liked,
+		UserId:    visitorIDString(visitor),
+	})
+}
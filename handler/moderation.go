@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"blog-backend/api"
+	"blog-backend/logger"
+	"blog-backend/model"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// Get the comment moderation queue
+// (GET /comments/moderation)
+func (h *Handler) GetCommentsModeration(ctx echo.Context, params api.GetCommentsModerationParams) error {
+	if ok, err := h.requireAdmin(ctx, "Only admins can view the moderation queue"); !ok {
+		return err
+	}
+	status := model.CommentStatusPending
+	if params.Status != nil {
+		status = string(*params.Status)
+	}
+	if !model.ValidCommentStatus(status) {
+		return ctx.JSON(http.StatusBadRequest, "Invalid status")
+	}
+	comments, err := h.Repo.GetModerationQueue(ctx.Request().Context(), status)
+	if err != nil {
+		logger.Println("GetModerationQueue Error: ", err)
+		return ctx.JSON(http.StatusInternalServerError, err)
+	}
+	return ctx.JSON(http.StatusOK, convertCommentsToAPIComments(comments, true))
+}
+
+// Approve comments
+// (POST /comments/moderation/approve)
+func (h *Handler) PostCommentsModerationApprove(ctx echo.Context) error {
+	if ok, err := h.requireAdmin(ctx, "Only admins can approve comments"); !ok {
+		return err
+	}
+	var req api.PostCommentsModerationApproveJSONRequestBody
+	if err := ctx.Bind(&req); err != nil {
+		return ctx.JSON(http.StatusBadRequest, err)
+	}
+	return h.setCommentsStatus(ctx, req.Ids, model.CommentStatusApproved)
+}
+
+// Reject comments
+// (POST /comments/moderation/reject)
+func (h *Handler) PostCommentsModerationReject(ctx echo.Context) error {
+	if ok, err := h.requireAdmin(ctx, "Only admins can reject comments"); !ok {
+		return err
+	}
+	var req api.PostCommentsModerationRejectJSONRequestBody
+	if err := ctx.Bind(&req); err != nil {
+		return ctx.JSON(http.StatusBadRequest, err)
+	}
+	status := model.CommentStatusRejected
+	if req.Spam != nil && *req.Spam {
+		status = model.CommentStatusSpam
+	}
+	return h.setCommentsStatus(ctx, req.Ids, status)
+}
+
+// setCommentsStatus - コメントの状態をまとめて変更し、変更した件数を返す
+func (h *Handler) setCommentsStatus(ctx echo.Context, ids []string, status string) error {
+	if len(ids) == 0 {
+		return ctx.JSON(http.StatusBadRequest, "ids is required")
+	}
+	commentIds := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		commentId, err := uuid.Parse(id)
+		if err != nil {
+			return ctx.JSON(http.StatusBadRequest, err)
+		}
+		commentIds = append(commentIds, commentId)
+	}
+	updated, err := h.Repo.SetCommentsStatus(ctx.Request().Context(), commentIds, status)
+	if err != nil {
+		logger.Println("SetCommentsStatus Error: ", err)
+		return ctx.JSON(http.StatusInternalServerError, err)
+	}
+	count := int(updated)
+	return ctx.JSON(http.StatusOK, api.ModerationResult{Updated: &count})
+}
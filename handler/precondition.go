@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"blog-backend/api"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Cache-Controlのポリシー (CDNがキャッシュしてよい期間)
+// 記事は閲覧者ごとのいいね・リアクション状態を含むので共有キャッシュには載せない
+const (
+	articleCacheControl  = "private, max-age=60"
+	taxonomyCacheControl = "public, max-age=300"
+)
+
+// contentHash - レスポンスの内容から作るハッシュ。書き込みで内容が変われば値も変わるので、ETagの無効化は不要
+func contentHash(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:8])
+}
+
+// articleETag - 記事の版数と返す内容から作る強いETag ("v<版数>-<ハッシュ>")
+// 閲覧するたびに増えるview_countは内容のハッシュに含めない
+func articleETag(version int, representation api.Article) (string, error) {
+	representation.ViewCount = nil
+	body, err := json.Marshal(representation)
+	if err != nil {
+		return "", err
+	}
+	return `"` + articleVersionTag(version) + "-" + contentHash(body) + `"`, nil
+}
+
+func articleVersionTag(version int) string {
+	return "v" + strconv.Itoa(version)
+}
+
+// versionFromETag - ETagから記事の版数を取り出す。弱いETagや形式の違うETagはfalseを返す
+func versionFromETag(etag string) (int, bool) {
+	if !strings.HasPrefix(etag, `"v`) || !strings.HasSuffix(etag, `"`) || len(etag) < 3 {
+		return 0, false
+	}
+	tag := strings.Trim(etag, `"`)[1:]
+	if i := strings.Index(tag, "-"); i >= 0 {
+		tag = tag[:i]
+	}
+	version, err := strconv.Atoi(tag)
+	return version, err == nil
+}
+
+// matchesIfMatch - If-Matchヘッダーのいずれかが記事の現在の版数と一致するかを調べる
+// いいね数や翻訳のように編集と関係なく変わる部分があるため、ETagのうち版数だけを比べる
+func matchesIfMatch(header string, version int) bool {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" {
+			return true
+		}
+		if v, ok := versionFromETag(tag); ok && v == version {
+			return true
+		}
+	}
+	return false
+}
+
+// checkArticleIfMatch - If-Matchがない場合は428、古い版数の場合は412を書き込んでfalseを返す
+func checkArticleIfMatch(ctx echo.Context, version int) (bool, error) {
+	header := ctx.Request().Header.Get("If-Match")
+	if header == "" {
+		return false, ctx.JSON(http.StatusPreconditionRequired, "If-Match header is required")
+	}
+	if !matchesIfMatch(header, version) {
+		return false, articleVersionConflict(ctx, version)
+	}
+	return true, nil
+}
+
+// articleVersionConflict - 現在の版数を412で返し、クライアントが編集をやり直せるようにする
+func articleVersionConflict(ctx echo.Context, version int) error {
+	message := "Article has been modified by someone else"
+	etag := `"` + articleVersionTag(version) + `"`
+	ctx.Response().Header().Set("ETag", etag)
+	return ctx.JSON(http.StatusPreconditionFailed, api.VersionConflict{
+		Message: &message,
+		Version: &version,
+		Etag:    &etag,
+	})
+}
+
+// weakContentETag - 内容のハッシュから作る弱いETag
+func weakContentETag(body []byte) string {
+	return `W/"` + contentHash(body) + `"`
+}
+
+// respondWithValidators - ETag・Last-Modified・Cache-Controlを付けてJSONを返す
+// If-None-MatchまたはIf-Modified-Sinceが一致した場合は304を返す。lastModifiedがゼロの場合はLast-Modifiedを付けない
+func respondWithValidators(ctx echo.Context, body []byte, etag string, lastModified time.Time, cacheControl string) error {
+	header := ctx.Response().Header()
+	header.Set("ETag", etag)
+	header.Set(echo.HeaderCacheControl, cacheControl)
+	if !lastModified.IsZero() {
+		header.Set(echo.HeaderLastModified, lastModified.UTC().Format(http.TimeFormat))
+	}
+	if notModified(ctx.Request(), etag, lastModified) {
+		return ctx.NoContent(http.StatusNotModified)
+	}
+	return ctx.JSONBlob(http.StatusOK, body)
+}
+
+// notModified - If-None-Matchがある場合はそれだけを弱い比較で調べ、ない場合はIf-Modified-Sinceを調べる
+func notModified(req *http.Request, etag string, lastModified time.Time) bool {
+	if ifNoneMatch := req.Header.Get("If-None-Match"); ifNoneMatch != "" {
+		for _, tag := range strings.Split(ifNoneMatch, ",") {
+			tag = strings.TrimSpace(tag)
+			if tag == "*" || strings.TrimPrefix(tag, "W/") == strings.TrimPrefix(etag, "W/") {
+				return true
+			}
+		}
+		return false
+	}
+	if lastModified.IsZero() {
+		return false
+	}
+	since, err := http.ParseTime(req.Header.Get(echo.HeaderIfModifiedSince))
+	if err != nil {
+		return false
+	}
+	// HTTPの日時は秒単位なので切り捨てて比べる
+	return !lastModified.Truncate(time.Second).After(since)
+}
@@ -0,0 +1,156 @@
+package handler
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"blog-backend/api"
+	"blog-backend/logger"
+	"blog-backend/model"
+)
+
+// Get reactions for an article or comment
+// (GET /reactions)
+func (h *Handler) GetReactions(ctx echo.Context, params api.GetReactionsParams) error {
+	targetID, err := uuid.Parse(params.TargetId)
+	if err != nil || !model.ValidReactionTarget(string(params.TargetType)) {
+		return ctx.JSON(http.StatusBadRequest, "invalid reaction target")
+	}
+	if ok, err := h.reactionTargetExists(ctx, params.TargetType, targetID); !ok {
+		return err
+	}
+	visitor, err := currentVisitor(ctx, h.Repo)
+	if err != nil {
+		logger.Println("currentVisitor Error: ", err)
+		return ctx.JSON(http.StatusInternalServerError, err)
+	}
+	return h.reactionResponse(ctx, params.TargetType, targetID, visitor)
+}
+
+// Add a reaction
+// (POST /reactions)
+func (h *Handler) PostReactions(ctx echo.Context) error {
+	var req api.PostReactionsJSONRequestBody
+	if err := ctx.Bind(&req); err != nil {
+		return ctx.JSON(http.StatusBadRequest, "invalid request")
+	}
+	targetID, err := uuid.Parse(req.TargetId)
+	if err != nil || !model.ValidReactionTarget(string(req.TargetType)) {
+		return ctx.JSON(http.StatusBadRequest, "invalid reaction target")
+	}
+	// ログインしていればそのユーザー、していなければIPアドレスでユーザーを特定する
+	visitor, err := currentVisitor(ctx, h.Repo)
+	if err != nil {
+		logger.Println("currentVisitor Error: ", err)
+		return ctx.JSON(http.StatusInternalServerError, err)
+	}
+	if !visitor.Valid {
+		userID, err := h.Repo.CheckIPAddressAndReturnUserID(ctx.Request().Context(), ctx.RealIP())
+		if err != nil {
+			logger.Println("CheckIPAddressAndReturnUserID Error: ", err)
+			return ctx.JSON(http.StatusInternalServerError, err)
+		}
+		visitor = uuid.NullUUID{UUID: userID, Valid: true}
+	}
+	_, err = h.Repo.AddReaction(ctx.Request().Context(), string(req.TargetType), targetID, visitor.UUID, req.Emoji)
+	if err == model.ErrInvalidReactionEmoji {
+		return ctx.JSON(http.StatusBadRequest, err.Error())
+	}
+	if err == model.ErrReactionTargetNotFound {
+		return ctx.JSON(http.StatusNotFound, err.Error())
+	}
+	if err != nil {
+		logger.Println("AddReaction Error: ", err)
+		return ctx.JSON(http.StatusInternalServerError, err)
+	}
+	return h.reactionResponse(ctx, req.TargetType, targetID, visitor)
+}
+
+// Remove a reaction
+// (DELETE /reactions)
+func (h *Handler) DeleteReactions(ctx echo.Context, params api.DeleteReactionsParams) error {
+	targetID, err := uuid.Parse(params.TargetId)
+	if err != nil || !model.ValidReactionTarget(string(params.TargetType)) {
+		return ctx.JSON(http.StatusBadRequest, "invalid reaction target")
+	}
+	visitor, err := currentVisitor(ctx, h.Repo)
+	if err != nil {
+		logger.Println("currentVisitor Error: ", err)
+		return ctx.JSON(http.StatusInternalServerError, err)
+	}
+	// 訪問者を特定できない場合は外すリアクションもない
+	if visitor.Valid {
+		_, err = h.Repo.RemoveReaction(ctx.Request().Context(), string(params.TargetType), targetID, visitor.UUID, params.Emoji)
+		if err == model.ErrReactionTargetNotFound {
+			return ctx.JSON(http.StatusNotFound, err.Error())
+		}
+		if err != nil {
+			logger.Println("RemoveReaction Error: ", err)
+			return ctx.JSON(http.StatusInternalServerError, err)
+		}
+	} else if ok, err := h.reactionTargetExists(ctx, params.TargetType, targetID); !ok {
+		return err
+	}
+	return h.reactionResponse(ctx, params.TargetType, targetID, visitor)
+}
+
+// reactionTargetExists - 対象の記事・コメントが見つからない場合は404を書き込んでfalseを返す
+func (h *Handler) reactionTargetExists(ctx echo.Context, target api.ReactionTarget, targetID uuid.UUID) (bool, error) {
+	var err error
+	switch target {
+	case api.ReactionTargetArticle:
+		_, err = h.Repo.GetArticleByID(ctx.Request().Context(), targetID)
+	case api.ReactionTargetComment:
+		_, err = h.Repo.GetCommentByID(ctx.Request().Context(), targetID)
+	}
+	if err == sql.ErrNoRows {
+		return false, ctx.JSON(http.StatusNotFound, model.ErrReactionTargetNotFound.Error())
+	}
+	if err != nil {
+		logger.Println("reactionTargetExists Error: ", err)
+		return false, ctx.JSON(http.StatusInternalServerError, err)
+	}
+	return true, nil
+}
+
+// reactionResponse - 対象の現在のリアクション数を返す
+func (h *Handler) reactionResponse(ctx echo.Context, target api.ReactionTarget, targetID uuid.UUID, visitor uuid.NullUUID) error {
+	summaries, err := h.Repo.GetReactionSummaries(ctx.Request().Context(), string(target), []uuid.UUID{targetID}, visitor)
+	if err != nil {
+		logger.Println("GetReactionSummaries Error: ", err)
+		return ctx.JSON(http.StatusInternalServerError, err)
+	}
+	reactions := convertReactionSummariesToAPIReactionSummaries(summaries[targetID])
+	targetIDStr := targetID.String()
+	return ctx.JSON(http.StatusOK, api.ReactionResponse{
+		TargetType: &target,
+		TargetId:   &targetIDStr,
+		Reactions:  &reactions,
+	})
+}
+
+// attachCommentReactions - コメント一覧にリアクション数と訪問者自身のリアクションを付ける
+func (h *Handler) attachCommentReactions(ctx echo.Context, comments []api.Comment) error {
+	visitor, err := currentVisitor(ctx, h.Repo)
+	if err != nil {
+		logger.Println("currentVisitor Error: ", err)
+		return err
+	}
+	ids := make([]uuid.UUID, 0, len(comments))
+	for _, comment := range comments {
+		ids = append(ids, uuid.MustParse(*comment.Id))
+	}
+	summaries, err := h.Repo.GetReactionSummaries(ctx.Request().Context(), model.ReactionTargetComment, ids, visitor)
+	if err != nil {
+		logger.Println("GetReactionSummaries Error: ", err)
+		return err
+	}
+	for i := range comments {
+		reactions := convertReactionSummariesToAPIReactionSummaries(summaries[uuid.MustParse(*comments[i].Id)])
+		comments[i].Reactions = &reactions
+	}
+	return nil
+}
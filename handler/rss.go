@@ -1,6 +1,8 @@
 package handler
 
 import (
+	"blog-backend/api"
+	"blog-backend/model"
 	"net/http"
 
 	"github.com/labstack/echo/v4"
@@ -8,7 +10,7 @@ import (
 
 // Get RSS feed
 // (GET /rss)
-func (h *Handler) GetRss(ctx echo.Context) error {
+func (h *Handler) GetRss(ctx echo.Context, params api.GetRssParams) error {
 	// limit := 5
 	// articles, err := h.Repo.GetArticles(ctx, &limit)
 	// if err != nil {
@@ -23,6 +25,15 @@ func (h *Handler) GetRss(ctx echo.Context) error {
 	// 	return ctx.JSON(http.StatusInternalServerError, err)
 	// }
 
+	// 言語が指定された場合は、その言語のRSSフィードのURLを返す
+	if params.Lang != nil {
+		lang := model.NormalizeLanguage(*params.Lang)
+		if !model.IsSupportedLanguage(lang) {
+			return ctx.JSON(http.StatusBadRequest, "Unsupported language")
+		}
+		return ctx.JSON(http.StatusOK, h.Config.BaseURL+"/rss/rss."+lang+".xml")
+	}
+
 	// 処理が成功したことを示すレスポンスとしてRSSフィードのURLを返す
 	return ctx.JSON(http.StatusOK, h.Config.BaseURL+"/rss/rss.xml")
 }
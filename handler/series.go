@@ -0,0 +1,236 @@
+package handler
+
+import (
+	"blog-backend/api"
+	"blog-backend/logger"
+	"blog-backend/model"
+	"database/sql"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// Get a list of series
+// (GET /series)
+func (h *Handler) GetSeries(ctx echo.Context) error {
+	seriesList, err := h.Repo.GetSeriesList(ctx.Request().Context())
+	if err != nil {
+		logger.Println("GetSeriesList Error: ", err)
+		return ctx.JSON(http.StatusInternalServerError, err)
+	}
+	apiSeriesList := make([]api.Series, 0)
+	for _, series := range seriesList {
+		articleIDs, err := h.Repo.GetSeriesArticleIDs(ctx.Request().Context(), series.ID)
+		if err != nil {
+			logger.Println("GetSeriesArticleIDs Error: ", err)
+			return ctx.JSON(http.StatusInternalServerError, err)
+		}
+		apiSeriesList = append(apiSeriesList, convertSeriesToAPISeries(series, articleIDs, nil))
+	}
+	return ctx.JSON(http.StatusOK, apiSeriesList)
+}
+
+// Create a new series
+// (POST /series)
+func (h *Handler) PostSeries(ctx echo.Context) error {
+	var req api.PostSeriesJSONRequestBody
+	if err := ctx.Bind(&req); err != nil {
+		return ctx.JSON(http.StatusBadRequest, err)
+	}
+	if req.Title == "" {
+		return ctx.JSON(http.StatusBadRequest, "title is required")
+	}
+
+	seriesID := uuid.New()
+	articleIDs := make([]uuid.UUID, 0)
+	if req.ArticleIds != nil {
+		ids, message := h.validateSeriesArticleIDs(ctx, seriesID, *req.ArticleIds)
+		if message != "" {
+			return ctx.JSON(http.StatusBadRequest, message)
+		}
+		articleIDs = ids
+	}
+
+	series := model.Series{
+		ID:          seriesID,
+		Title:       req.Title,
+		Description: convertStringPointToNullString(req.Description),
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+	err := h.Repo.CreateSeries(ctx.Request().Context(), series, articleIDs)
+	if err != nil {
+		logger.Println("CreateSeries Error: ", err)
+		return ctx.JSON(http.StatusInternalServerError, err)
+	}
+	return ctx.JSON(http.StatusCreated, convertSeriesToAPISeries(series, articleIDs, nil))
+}
+
+// Delete a series
+// (DELETE /series/{id})
+func (h *Handler) DeleteSeriesId(ctx echo.Context, id string) error {
+	seriesID, err := uuid.Parse(id)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, err)
+	}
+	_, err = h.Repo.GetSeriesByID(ctx.Request().Context(), seriesID)
+	if err == sql.ErrNoRows {
+		return ctx.JSON(http.StatusNotFound, "Series not found")
+	}
+	if err != nil {
+		return ctx.JSON(http.StatusInternalServerError, err)
+	}
+	err = h.Repo.DeleteSeries(ctx.Request().Context(), seriesID)
+	if err != nil {
+		logger.Println("DeleteSeries Error: ", err)
+		return ctx.JSON(http.StatusInternalServerError, err)
+	}
+	return ctx.JSON(http.StatusNoContent, nil)
+}
+
+// Get series details
+// (GET /series/{id})
+func (h *Handler) GetSeriesId(ctx echo.Context, id string) error {
+	seriesID, err := uuid.Parse(id)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, err)
+	}
+	series, err := h.Repo.GetSeriesByID(ctx.Request().Context(), seriesID)
+	if err == sql.ErrNoRows {
+		return ctx.JSON(http.StatusNotFound, "Series not found")
+	}
+	if err != nil {
+		return ctx.JSON(http.StatusInternalServerError, err)
+	}
+	articles, err := h.Repo.GetSeriesArticles(ctx.Request().Context(), seriesID)
+	if err != nil {
+		logger.Println("GetSeriesArticles Error: ", err)
+		return ctx.JSON(http.StatusInternalServerError, err)
+	}
+	apiArticles, err := convertArticlesToAPIArticles(ctx, articles, h.Repo)
+	if err != nil {
+		logger.Println("Convert articles error: ", err)
+		return ctx.JSON(http.StatusInternalServerError, err)
+	}
+	// convertArticlesToAPIArticlesは作成日時順に並べ替えるので、シリーズの順番に戻す
+	sortAPIArticlesBySeriesPart(apiArticles)
+
+	articleIDs := make([]uuid.UUID, 0, len(articles))
+	for _, article := range articles {
+		articleIDs = append(articleIDs, article.ID)
+	}
+	return ctx.JSON(http.StatusOK, convertSeriesToAPISeries(series, articleIDs, apiArticles))
+}
+
+// Update a series
+// (PATCH /series/{id})
+func (h *Handler) PatchSeriesId(ctx echo.Context, id string) error {
+	seriesID, err := uuid.Parse(id)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, err)
+	}
+	var req api.PatchSeriesIdJSONRequestBody
+	if err := ctx.Bind(&req); err != nil {
+		return ctx.JSON(http.StatusBadRequest, err)
+	}
+
+	series, err := h.Repo.GetSeriesByID(ctx.Request().Context(), seriesID)
+	if err == sql.ErrNoRows {
+		return ctx.JSON(http.StatusNotFound, "Series not found")
+	}
+	if err != nil {
+		return ctx.JSON(http.StatusInternalServerError, err)
+	}
+
+	if req.Title != nil {
+		if *req.Title == "" {
+			return ctx.JSON(http.StatusBadRequest, "title must not be empty")
+		}
+		series.Title = *req.Title
+	}
+	if req.Description != nil {
+		series.Description = convertStringPointToNullString(req.Description)
+	}
+	series.UpdatedAt = time.Now()
+
+	// article_idsが指定されていない場合はnilのままにして、並びを変更しない
+	var articleIDs []uuid.UUID
+	if req.ArticleIds != nil {
+		ids, message := h.validateSeriesArticleIDs(ctx, seriesID, *req.ArticleIds)
+		if message != "" {
+			return ctx.JSON(http.StatusBadRequest, message)
+		}
+		articleIDs = ids
+	}
+
+	err = h.Repo.UpdateSeries(ctx.Request().Context(), series, articleIDs)
+	if err != nil {
+		logger.Println("UpdateSeries Error: ", err)
+		return ctx.JSON(http.StatusInternalServerError, err)
+	}
+	if articleIDs == nil {
+		articleIDs, err = h.Repo.GetSeriesArticleIDs(ctx.Request().Context(), seriesID)
+		if err != nil {
+			return ctx.JSON(http.StatusInternalServerError, err)
+		}
+	}
+	return ctx.JSON(http.StatusOK, convertSeriesToAPISeries(series, articleIDs, nil))
+}
+
+// Get RSS feed of a series
+// (GET /series/{id}/rss)
+func (h *Handler) GetSeriesIdRss(ctx echo.Context, id string) error {
+	seriesID, err := uuid.Parse(id)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, err)
+	}
+	series, err := h.Repo.GetSeriesByID(ctx.Request().Context(), seriesID)
+	if err == sql.ErrNoRows {
+		return ctx.JSON(http.StatusNotFound, "Series not found")
+	}
+	if err != nil {
+		return ctx.JSON(http.StatusInternalServerError, err)
+	}
+	articles, err := h.Repo.GetSeriesArticles(ctx.Request().Context(), seriesID)
+	if err != nil {
+		logger.Println("GetSeriesArticles Error: ", err)
+		return ctx.JSON(http.StatusInternalServerError, err)
+	}
+	rss, err := h.Config.SeriesRSSmaker(ctx.Request().Context(), series, articles)
+	if err != nil {
+		return ctx.JSON(http.StatusInternalServerError, err)
+	}
+	return ctx.Blob(http.StatusOK, "application/rss+xml; charset=UTF-8", []byte(rss))
+}
+
+// validateSeriesArticleIDs - 記事IDを検証し、問題があればエラーメッセージを返す
+func (h *Handler) validateSeriesArticleIDs(ctx echo.Context, seriesID uuid.UUID, ids []string) ([]uuid.UUID, string) {
+	articleIDs := make([]uuid.UUID, 0, len(ids))
+	seen := make(map[uuid.UUID]bool)
+	for _, id := range ids {
+		articleID, err := uuid.Parse(id)
+		if err != nil {
+			return nil, "invalid article id: " + id
+		}
+		if seen[articleID] {
+			return nil, "duplicate article id: " + id
+		}
+		seen[articleID] = true
+		if _, err := h.Repo.GetArticleByID(ctx.Request().Context(), articleID); err != nil {
+			return nil, "article not found: " + id
+		}
+		// 記事は1つのシリーズにしか所属できない
+		currentSeriesID, err := h.Repo.GetSeriesIDByArticle(ctx.Request().Context(), articleID)
+		if err != nil {
+			logger.Println("GetSeriesIDByArticle Error: ", err)
+			return nil, "failed to check series membership: " + id
+		}
+		if currentSeriesID != uuid.Nil && currentSeriesID != seriesID {
+			return nil, "article already belongs to another series: " + id
+		}
+		articleIDs = append(articleIDs, articleID)
+	}
+	return articleIDs, ""
+}
@@ -0,0 +1,145 @@
+package handler
+
+import (
+	"blog-backend/api"
+	"blog-backend/logger"
+	"blog-backend/model"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+var (
+	tokenSecret     []byte
+	tokenSecretOnce sync.Once
+)
+
+// authTokenSecret - トークンの署名に使う鍵。AUTH_TOKEN_SECRETがない場合は起動ごとに作るため、再起動するとログインし直しになる
+func authTokenSecret() []byte {
+	tokenSecretOnce.Do(func() {
+		if secret := os.Getenv("AUTH_TOKEN_SECRET"); secret != "" {
+			tokenSecret = []byte(secret)
+			return
+		}
+		logger.Println("AUTH_TOKEN_SECRET is not set, using a random secret")
+		tokenSecret = make([]byte, 32)
+		if _, err := rand.Read(tokenSecret); err != nil {
+			panic(err)
+		}
+	})
+	return tokenSecret
+}
+
+// authTokenTTL - ログインしてからトークンが使える期間 (AUTH_TOKEN_TTL_HOURS、既定7日)
+func authTokenTTL() time.Duration {
+	hours, err := strconv.Atoi(os.Getenv("AUTH_TOKEN_TTL_HOURS"))
+	if err != nil || hours <= 0 {
+		hours = 24 * 7
+	}
+	return time.Duration(hours) * time.Hour
+}
+
+// issueAuthToken - "<ユーザーID>.<有効期限>.<署名>"の形のBearerトークンを作る
+func issueAuthToken(userID uuid.UUID, expiresAt time.Time) string {
+	payload := userID.String() + "." + strconv.FormatInt(expiresAt.Unix(), 10)
+	return payload + "." + signAuthToken(payload)
+}
+
+func signAuthToken(payload string) string {
+	mac := hmac.New(sha256.New, authTokenSecret())
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// parseAuthToken - 署名と有効期限を確かめてユーザーIDを返す
+func parseAuthToken(token string) (uuid.UUID, bool) {
+	i := strings.LastIndex(token, ".")
+	if i < 0 {
+		return uuid.Nil, false
+	}
+	payload, signature := token[:i], token[i+1:]
+	if !hmac.Equal([]byte(signature), []byte(signAuthToken(payload))) {
+		return uuid.Nil, false
+	}
+	parts := strings.Split(payload, ".")
+	if len(parts) != 2 {
+		return uuid.Nil, false
+	}
+	expiresAt, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil || time.Now().Unix() > expiresAt {
+		return uuid.Nil, false
+	}
+	userID, err := uuid.Parse(parts[0])
+	return userID, err == nil
+}
+
+// authenticatedUser - AuthorizationヘッダーのBearerトークンからログイン中のユーザーを返す。ログインしていない場合はnil
+func (h *Handler) authenticatedUser(ctx echo.Context) (*model.User, error) {
+	header := ctx.Request().Header.Get(echo.HeaderAuthorization)
+	if !strings.HasPrefix(header, "Bearer ") {
+		return nil, nil
+	}
+	userID, ok := parseAuthToken(strings.TrimPrefix(header, "Bearer "))
+	if !ok {
+		return nil, nil
+	}
+	user, err := h.Repo.GetUserByID(ctx.Request().Context(), userID)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// requireAdmin - 管理者でない場合は403を書き込んでfalseを返す
+func (h *Handler) requireAdmin(ctx echo.Context, message string) (bool, error) {
+	user, err := h.authenticatedUser(ctx)
+	if err != nil {
+		logger.Println("authenticatedUser Error: ", err)
+		return false, ctx.JSON(http.StatusInternalServerError, err)
+	}
+	if user == nil || !user.IsAdmin {
+		return false, forbidden(ctx, message)
+	}
+	return true, nil
+}
+
+// currentVisitor - リアクションなどを付けた訪問者。ログイン中のユーザー、なければIPアドレスで特定したユーザー
+// 訪問者を記録したことがないIPアドレスの場合は無効な値を返す (ユーザーは作らない)
+func currentVisitor(ctx echo.Context, repo *model.Repository) (uuid.NullUUID, error) {
+	header := ctx.Request().Header.Get(echo.HeaderAuthorization)
+	if strings.HasPrefix(header, "Bearer ") {
+		if userID, ok := parseAuthToken(strings.TrimPrefix(header, "Bearer ")); ok {
+			return uuid.NullUUID{UUID: userID, Valid: true}, nil
+		}
+	}
+	user, err := repo.GetUserByIpAddress(ctx.Request().Context(), ctx.RealIP())
+	if err == sql.ErrNoRows {
+		return uuid.NullUUID{}, nil
+	}
+	if err != nil {
+		return uuid.NullUUID{}, err
+	}
+	return uuid.NullUUID{UUID: user.ID, Valid: true}, nil
+}
+
+// forbidden - 403を標準のエラー形式で返す
+func forbidden(ctx echo.Context, message string) error {
+	return ctx.JSON(http.StatusForbidden, api.ErrorResponse{
+		Code:    http.StatusForbidden,
+		Message: message,
+	})
+}
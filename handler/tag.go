@@ -2,8 +2,14 @@ package handler
 
 import (
 	"blog-backend/api"
+	"blog-backend/logger"
 	"blog-backend/model"
+	"database/sql"
+	"encoding/json"
 	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
@@ -22,12 +28,18 @@ func (h *Handler) GetTags(ctx echo.Context) error {
 	apiTags := make([]api.Tag, 0)
 	for _, tag := range tags {
 		id := tag.ID.String()
+		articleCount := tag.ArticleCount
 		apiTags = append(apiTags, api.Tag{
-			Id:   &id,
-			Name: &tag.Name,
+			Id:           &id,
+			Name:         &tag.Name,
+			ArticleCount: &articleCount,
 		})
 	}
-	return ctx.JSON(http.StatusOK, apiTags)
+	body, err := json.Marshal(apiTags)
+	if err != nil {
+		return ctx.JSON(http.StatusInternalServerError, err)
+	}
+	return respondWithValidators(ctx, body, weakContentETag(body), time.Time{}, taxonomyCacheControl)
 }
 
 // Create a new tag
@@ -59,8 +71,14 @@ func (h *Handler) PostTagsArticleId(ctx echo.Context, articleId string) error {
 	if err := ctx.Bind(&req); err != nil {
 		return ctx.JSON(http.StatusBadRequest, err)
 	}
-	article_id := uuid.MustParse(articleId)
-	err := h.Repo.AddTag(ctx.Request().Context(), model.Tag{
+	article_id, err := uuid.Parse(articleId)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, err)
+	}
+	if req.Tag.Name == nil {
+		return ctx.JSON(http.StatusBadRequest, "tag name is required")
+	}
+	err = h.Repo.AddTag(ctx.Request().Context(), model.Tag{
 		ID:        uuid.New(),
 		ArticleID: article_id,
 		Name:      *req.Tag.Name,
@@ -70,3 +88,282 @@ func (h *Handler) PostTagsArticleId(ctx echo.Context, articleId string) error {
 	}
 	return ctx.JSON(http.StatusCreated, "Tag added")
 }
+
+// Rename a tag
+// (PATCH /tags/{id})
+func (h *Handler) PatchTagsId(ctx echo.Context, id string) error {
+	if ok, err := h.requireAdmin(ctx, "Only admins can rename tags"); !ok {
+		return err
+	}
+	tagId, err := uuid.Parse(id)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, err)
+	}
+	var req api.PatchTagsIdJSONRequestBody
+	if err := ctx.Bind(&req); err != nil {
+		return ctx.JSON(http.StatusBadRequest, err)
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return ctx.JSON(http.StatusBadRequest, "name must not be empty")
+	}
+	before, err := h.Repo.GetTagNameByID(ctx.Request().Context(), tagId)
+	if err == sql.ErrNoRows {
+		return ctx.JSON(http.StatusNotFound, "Tag not found")
+	}
+	if err != nil {
+		logger.Println("GetTagNameByID Error: ", err)
+		return ctx.JSON(http.StatusInternalServerError, err)
+	}
+	err = h.Repo.RenameTag(ctx.Request().Context(), tagId, name)
+	if err == model.ErrTagNotFound {
+		return ctx.JSON(http.StatusNotFound, "Tag not found")
+	}
+	if err == model.ErrTagNameTaken {
+		return ctx.JSON(http.StatusConflict, err.Error())
+	}
+	if err != nil {
+		logger.Println("RenameTag Error: ", err)
+		return ctx.JSON(http.StatusInternalServerError, err)
+	}
+	if before.Name != name {
+		h.regenerateTaggedArticles(ctx, tagId)
+	}
+	return h.tagResponse(ctx, tagId)
+}
+
+// Delete a tag
+// (DELETE /tags/{id})
+func (h *Handler) DeleteTagsId(ctx echo.Context, id string) error {
+	if ok, err := h.requireAdmin(ctx, "Only admins can delete tags"); !ok {
+		return err
+	}
+	tagId, err := uuid.Parse(id)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, err)
+	}
+	deleted, err := h.Repo.DeleteTag(ctx.Request().Context(), tagId)
+	if err != nil {
+		logger.Println("DeleteTag Error: ", err)
+		return ctx.JSON(http.StatusInternalServerError, err)
+	}
+	if !deleted {
+		return ctx.JSON(http.StatusNotFound, "Tag not found")
+	}
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+// Merge tags
+// (POST /tags/merge)
+func (h *Handler) PostTagsMerge(ctx echo.Context) error {
+	if ok, err := h.requireAdmin(ctx, "Only admins can merge tags"); !ok {
+		return err
+	}
+	var req api.PostTagsMergeJSONRequestBody
+	if err := ctx.Bind(&req); err != nil {
+		return ctx.JSON(http.StatusBadRequest, err)
+	}
+	targetId, err := uuid.Parse(req.TargetId)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, err)
+	}
+	if len(req.SourceIds) == 0 {
+		return ctx.JSON(http.StatusBadRequest, "sourceIds must not be empty")
+	}
+	sourceIds := make([]uuid.UUID, 0, len(req.SourceIds))
+	for _, sourceId := range req.SourceIds {
+		id, err := uuid.Parse(sourceId)
+		if err != nil {
+			return ctx.JSON(http.StatusBadRequest, err)
+		}
+		sourceIds = append(sourceIds, id)
+	}
+	_, err = h.Repo.MergeTags(ctx.Request().Context(), sourceIds, targetId)
+	if err == model.ErrTagNotFound {
+		return ctx.JSON(http.StatusNotFound, err.Error())
+	}
+	if err != nil {
+		logger.Println("MergeTags Error: ", err)
+		return ctx.JSON(http.StatusInternalServerError, err)
+	}
+	// 元のタグが付いていた記事は、すべて統合先のタグが付いた記事に含まれる
+	h.regenerateTaggedArticles(ctx, targetId)
+	return h.tagResponse(ctx, targetId)
+}
+
+// regenerateTaggedArticles - タグの名前や付いている記事が変わったあと、タグが描かれたサムネイルとRSSを作り直す
+// タグの変更はすでに保存されているので、作り直せなかった記事はログに残して続ける
+func (h *Handler) regenerateTaggedArticles(ctx echo.Context, tagId uuid.UUID) {
+	articleIds, err := h.Repo.GetArticleIDsByTag(ctx.Request().Context(), tagId)
+	if err != nil {
+		logger.Println("GetArticleIDsByTag Error: ", err)
+		return
+	}
+	for _, articleId := range articleIds {
+		article, err := h.Repo.GetArticleByID(ctx.Request().Context(), articleId)
+		if err != nil {
+			logger.Println("GetArticleByID Error: ", err)
+			continue
+		}
+		if _, err := h.regenerateThumbnail(ctx, article); err != nil {
+			logger.Println("HandleThumbnailGeneration Error: ", err)
+		}
+	}
+	h.regenerateFeeds()
+}
+
+// tagResponse - 記事数を含めてタグを返す
+func (h *Handler) tagResponse(ctx echo.Context, tagId uuid.UUID) error {
+	tags, err := h.Repo.GetTagList(ctx.Request().Context())
+	if err != nil {
+		logger.Println("GetTagList Error: ", err)
+		return ctx.JSON(http.StatusInternalServerError, err)
+	}
+	for _, tag := range tags {
+		if tag.ID == tagId {
+			id := tag.ID.String()
+			articleCount := tag.ArticleCount
+			return ctx.JSON(http.StatusOK, api.Tag{
+				Id:           &id,
+				Name:         &tag.Name,
+				ArticleCount: &articleCount,
+			})
+		}
+	}
+	return ctx.JSON(http.StatusNotFound, "Tag not found")
+}
+
+// タグ名の最大の長さ (tags.nameの列の長さ)
+const maxTagNameLength = 100
+
+// Replace the tags of an article
+// (PUT /articles/{id}/tags)
+func (h *Handler) PutArticlesIdTags(ctx echo.Context, id string) error {
+	articleId, err := uuid.Parse(id)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, err)
+	}
+	var req api.PutArticlesIdTagsJSONRequestBody
+	if err := ctx.Bind(&req); err != nil {
+		return ctx.JSON(http.StatusBadRequest, err)
+	}
+	tagIds := make([]uuid.UUID, 0)
+	if req.TagIds != nil {
+		for _, tagId := range *req.TagIds {
+			parsed, err := uuid.Parse(tagId)
+			if err != nil {
+				return badTagRequest(ctx, "invalid tag id: "+tagId)
+			}
+			tagIds = append(tagIds, parsed)
+		}
+	}
+	names := make([]string, 0)
+	if req.Names != nil {
+		for _, name := range *req.Names {
+			name = strings.TrimSpace(name)
+			if name == "" {
+				return badTagRequest(ctx, "tag names must not be empty")
+			}
+			if utf8.RuneCountInString(name) > maxTagNameLength {
+				return badTagRequest(ctx, "tag names must be at most 100 characters")
+			}
+			names = append(names, name)
+		}
+	}
+	return h.updateArticleTags(ctx, articleId, "SetArticleTags", func(article model.Article) (model.Article, []model.TagItem, error) {
+		return h.Repo.SetArticleTags(ctx.Request().Context(), article, tagIds, names)
+	})
+}
+
+// Add a tag to an article
+// (POST /articles/{id}/tags/{tagId})
+func (h *Handler) PostArticlesIdTagsTagId(ctx echo.Context, id string, tagId string) error {
+	articleId, err := uuid.Parse(id)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, err)
+	}
+	tagUUID, err := uuid.Parse(tagId)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, err)
+	}
+	return h.updateArticleTags(ctx, articleId, "AddArticleTag", func(article model.Article) (model.Article, []model.TagItem, error) {
+		return h.Repo.AddArticleTag(ctx.Request().Context(), article, tagUUID)
+	})
+}
+
+// Remove a tag from an article
+// (DELETE /articles/{id}/tags/{tagId})
+func (h *Handler) DeleteArticlesIdTagsTagId(ctx echo.Context, id string, tagId string) error {
+	articleId, err := uuid.Parse(id)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, err)
+	}
+	tagUUID, err := uuid.Parse(tagId)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, err)
+	}
+	return h.updateArticleTags(ctx, articleId, "RemoveArticleTag", func(article model.Article) (model.Article, []model.TagItem, error) {
+		return h.Repo.RemoveArticleTag(ctx.Request().Context(), article, tagUUID)
+	})
+}
+
+// updateArticleTags - PATCH /articles/{id}と同じくIf-Matchを確かめてから記事のタグを変更し、変更したあとのタグを返す
+// タグが変わった場合はサムネイルとRSSを作り直し、新しい版数をETagで返す
+func (h *Handler) updateArticleTags(ctx echo.Context, articleId uuid.UUID, operation string, update func(model.Article) (model.Article, []model.TagItem, error)) error {
+	article, err := h.Repo.GetArticleByID(ctx.Request().Context(), articleId)
+	if err == sql.ErrNoRows {
+		return ctx.JSON(http.StatusNotFound, "Article not found")
+	}
+	if err != nil {
+		return ctx.JSON(http.StatusInternalServerError, err)
+	}
+	if ok, err := checkArticleIfMatch(ctx, article.Version); !ok {
+		return err
+	}
+
+	article.UpdatedAt = time.Now()
+	updated, tags, err := update(article)
+	if err == model.ErrVersionConflict {
+		// If-Matchの確認後に他の編集が入った
+		return h.currentArticleVersionConflict(ctx, articleId)
+	}
+	if unknown, ok := err.(*model.UnknownTagsError); ok {
+		if operation == "AddArticleTag" {
+			return ctx.JSON(http.StatusNotFound, "Tag not found")
+		}
+		return badTagRequest(ctx, unknown.Error())
+	}
+	if err != nil {
+		logger.Println(operation+" Error: ", err)
+		return ctx.JSON(http.StatusInternalServerError, err)
+	}
+
+	if updated.Version != article.Version {
+		// PATCHでタグを変えた場合と同じく、タグが描かれるサムネイルとRSSを作り直す
+		// タグはすでに保存されているので、サムネイルを作り直せなくてもログに残して成功を返す
+		if _, err := h.regenerateThumbnail(ctx, updated); err != nil {
+			logger.Println("HandleThumbnailGeneration Error: ", err)
+		}
+		h.regenerateFeeds()
+	}
+
+	apiTags := make([]api.Tag, 0, len(tags))
+	for _, tag := range tags {
+		id := tag.ID.String()
+		name := tag.Name
+		apiTags = append(apiTags, api.Tag{
+			Id:   &id,
+			Name: &name,
+		})
+	}
+	ctx.Response().Header().Set("ETag", `"`+articleVersionTag(updated.Version)+`"`)
+	return ctx.JSON(http.StatusOK, apiTags)
+}
+
+// badTagRequest - 400を標準のエラー形式で返す
+func badTagRequest(ctx echo.Context, message string) error {
+	return ctx.JSON(http.StatusBadRequest, api.ErrorResponse{
+		Code:    http.StatusBadRequest,
+		Message: message,
+	})
+}
@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"blog-backend/api"
+	"blog-backend/logger"
+	"blog-backend/model"
+	"database/sql"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// Get translations of an article
+// (GET /articles/{id}/translations)
+func (h *Handler) GetArticlesIdTranslations(ctx echo.Context, id string) error {
+	articleId, err := uuid.Parse(id)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, err)
+	}
+	_, err = h.Repo.GetArticleByID(ctx.Request().Context(), articleId)
+	if err == sql.ErrNoRows {
+		return ctx.JSON(http.StatusNotFound, "Article not found")
+	}
+	if err != nil {
+		return ctx.JSON(http.StatusInternalServerError, err)
+	}
+	translations, err := h.Repo.GetTranslationsByArticle(ctx.Request().Context(), articleId)
+	if err != nil {
+		logger.Println("GetTranslationsByArticle Error: ", err)
+		return ctx.JSON(http.StatusInternalServerError, err)
+	}
+	apiTranslations := make([]api.ArticleTranslation, 0)
+	for _, translation := range translations {
+		apiTranslations = append(apiTranslations, convertTranslationToAPITranslation(translation))
+	}
+	return ctx.JSON(http.StatusOK, apiTranslations)
+}
+
+// Delete a translation
+// (DELETE /articles/{id}/translations/{lang})
+func (h *Handler) DeleteArticlesIdTranslationsLang(ctx echo.Context, id string, lang string) error {
+	articleId, err := uuid.Parse(id)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, err)
+	}
+	lang = model.NormalizeLanguage(lang)
+	_, err = h.Repo.GetTranslation(ctx.Request().Context(), articleId, lang)
+	if err == sql.ErrNoRows {
+		return ctx.JSON(http.StatusNotFound, "Translation not found")
+	}
+	if err != nil {
+		return ctx.JSON(http.StatusInternalServerError, err)
+	}
+	err = h.Repo.DeleteTranslation(ctx.Request().Context(), articleId, lang)
+	if err != nil {
+		logger.Println("DeleteTranslation Error: ", err)
+		return ctx.JSON(http.StatusInternalServerError, err)
+	}
+	h.regenerateLanguageFeeds(ctx)
+	return ctx.JSON(http.StatusNoContent, nil)
+}
+
+// Create or update a translation
+// (PUT /articles/{id}/translations/{lang})
+func (h *Handler) PutArticlesIdTranslationsLang(ctx echo.Context, id string, lang string) error {
+	articleId, err := uuid.Parse(id)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, err)
+	}
+	lang = model.NormalizeLanguage(lang)
+	if !model.IsSupportedLanguage(lang) {
+		return ctx.JSON(http.StatusBadRequest, "Unsupported language")
+	}
+
+	var req api.PutArticlesIdTranslationsLangJSONRequestBody
+	if err := ctx.Bind(&req); err != nil {
+		return ctx.JSON(http.StatusBadRequest, err)
+	}
+	if req.Title == "" || req.Content == "" || req.Slug == "" {
+		return ctx.JSON(http.StatusBadRequest, "title, content and slug are required")
+	}
+
+	_, err = h.Repo.GetArticleByID(ctx.Request().Context(), articleId)
+	if err == sql.ErrNoRows {
+		return ctx.JSON(http.StatusNotFound, "Article not found")
+	}
+	if err != nil {
+		return ctx.JSON(http.StatusInternalServerError, err)
+	}
+
+	// slugは言語ごとに一意
+	sameSlug, err := h.Repo.GetTranslationBySlug(ctx.Request().Context(), lang, req.Slug)
+	if err == nil && sameSlug.ArticleID != articleId {
+		return ctx.JSON(http.StatusBadRequest, "Slug is already used by another article")
+	}
+	if err != nil && err != sql.ErrNoRows {
+		return ctx.JSON(http.StatusInternalServerError, err)
+	}
+
+	translation := model.ArticleTranslation{
+		ArticleID: articleId,
+		Lang:      lang,
+		Title:     req.Title,
+		Content:   req.Content,
+		Excerpt:   convertStringPointToNullString(req.Excerpt),
+		Slug:      req.Slug,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	err = h.Repo.UpsertTranslation(ctx.Request().Context(), translation)
+	if err != nil {
+		logger.Println("UpsertTranslation Error: ", err)
+		return ctx.JSON(http.StatusInternalServerError, err)
+	}
+	saved, err := h.Repo.GetTranslation(ctx.Request().Context(), articleId, lang)
+	if err != nil {
+		return ctx.JSON(http.StatusInternalServerError, err)
+	}
+	h.regenerateLanguageFeeds(ctx)
+	return ctx.JSON(http.StatusOK, convertTranslationToAPITranslation(saved))
+}
+
+func (h *Handler) regenerateLanguageFeeds(ctx echo.Context) {
+	err := h.Config.LanguageRSSmaker(ctx.Request().Context(), h.Repo)
+	if err != nil {
+		logger.Printf("Failed to generate language RSS feeds: %v", err)
+	}
+}
@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"blog-backend/api"
+	"blog-backend/logger"
+	"blog-backend/model"
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// Get trashed items
+// (GET /trash)
+func (h *Handler) GetTrash(ctx echo.Context) error {
+	if ok, err := h.requireAdmin(ctx, "Only admins can view the trash"); !ok {
+		return err
+	}
+	articles, err := h.Repo.GetTrashedArticles(ctx.Request().Context())
+	if err != nil {
+		logger.Println("GetTrashedArticles Error: ", err)
+		return ctx.JSON(http.StatusInternalServerError, err)
+	}
+	comments, err := h.Repo.GetTrashedComments(ctx.Request().Context())
+	if err != nil {
+		logger.Println("GetTrashedComments Error: ", err)
+		return ctx.JSON(http.StatusInternalServerError, err)
+	}
+	tags, err := h.Repo.GetTrashedTags(ctx.Request().Context())
+	if err != nil {
+		logger.Println("GetTrashedTags Error: ", err)
+		return ctx.JSON(http.StatusInternalServerError, err)
+	}
+	apiArticles := convertTrashItemsToAPITrashItems(articles)
+	apiComments := convertTrashItemsToAPITrashItems(comments)
+	apiTags := convertTrashItemsToAPITrashItems(tags)
+	return ctx.JSON(http.StatusOK, api.TrashResponse{
+		Articles: &apiArticles,
+		Comments: &apiComments,
+		Tags:     &apiTags,
+	})
+}
+
+// Restore a trashed article
+// (POST /trash/articles/{id}/restore)
+func (h *Handler) PostTrashArticlesIdRestore(ctx echo.Context, id string) error {
+	restored, err := h.restoreFromTrash(ctx, id, h.Repo.RestoreArticle, "Article")
+	if restored {
+		// 戻した記事をRSSフィードに反映する
+		h.regenerateFeeds()
+	}
+	return err
+}
+
+// Restore a trashed comment
+// (POST /trash/comments/{id}/restore)
+func (h *Handler) PostTrashCommentsIdRestore(ctx echo.Context, id string) error {
+	_, err := h.restoreFromTrash(ctx, id, h.Repo.RestoreComment, "Comment")
+	return err
+}
+
+// Restore a trashed tag
+// (POST /trash/tags/{id}/restore)
+func (h *Handler) PostTrashTagsIdRestore(ctx echo.Context, id string) error {
+	_, err := h.restoreFromTrash(ctx, id, h.Repo.RestoreTag, "Tag")
+	return err
+}
+
+// restoreFromTrash - ゴミ箱から戻してレスポンスを書き込む。戻せた場合はtrueを返す
+func (h *Handler) restoreFromTrash(ctx echo.Context, id string, restore func(context.Context, uuid.UUID) (bool, error), kind string) (bool, error) {
+	if ok, err := h.requireAdmin(ctx, "Only admins can restore items from the trash"); !ok {
+		return false, err
+	}
+	itemID, err := uuid.Parse(id)
+	if err != nil {
+		return false, ctx.JSON(http.StatusBadRequest, err)
+	}
+	restored, err := restore(ctx.Request().Context(), itemID)
+	if err != nil {
+		logger.Println("Restore"+kind+" Error: ", err)
+		return false, ctx.JSON(http.StatusInternalServerError, err)
+	}
+	if !restored {
+		return false, ctx.JSON(http.StatusNotFound, kind+" not found in trash")
+	}
+	return true, ctx.JSON(http.StatusNoContent, nil)
+}
+
+// regenerateFeeds - 記事の削除や復元のあと、RSSフィードを作り直す
+func (h *Handler) regenerateFeeds() {
+	if err := model.SetupFirstRss(h.Repo, h.Config); err != nil {
+		logger.Printf("Failed to generate RSS feed: %v", err)
+	}
+}
@@ -1,9 +1,14 @@
 package main
 
 import (
+	"context"
+	"net/http"
 	"os"
+	"os/signal"
 	"strconv"
 	"strings"
+	"syscall"
+	"time"
 
 	"blog-backend/api"
 	"blog-backend/handler"
@@ -58,14 +63,27 @@ func main() {
 	// setup repository
 	repo := model.New(db)
 
+	// `main repair-counters` でいいね数・コメント数を数え直して終了する
+	if len(os.Args) > 1 && os.Args[1] == "repair-counters" {
+		repaired, err := repo.RepairArticleCounters(context.Background())
+		if err != nil {
+			e.Logger.Fatal(err)
+		}
+		logger.Printf("Repaired counters of %d articles", repaired)
+		return
+	}
+
 	// setup configuration (5MBの上限など)
 	config := model.NewUploader("/app/uploads/images", os.Getenv("BASE_URL"), 5*1024*1024)
 
 	// Google Driveサービスセットアップ & ファイルダウンロード
 	driveService := model.SetupGoogleDrive()
 
+	// 閲覧数とanalysisの記録をまとめて書き込む
+	views := model.NewViewWriter(repo)
+
 	// ハンドラーにGoogle Driveサービスを渡す
-	h := handler.New(repo, config, driveService)
+	h := handler.New(repo, config, driveService, views)
 
 	// RSSフィードの初回生成
 	err = model.SetupFirstRss(repo, config)
@@ -73,8 +91,32 @@ func main() {
 		logger.Printf("Failed to setup RSS feed: %v", err)
 	}
 
+	// 保持期間を過ぎたゴミ箱の項目を1時間ごとに完全に削除
+	model.StartTrashPurger(repo, time.Hour)
+	// 閲覧数の重複排除に使った訪問者の記録を1時間ごとに削除
+	model.StartArticleViewCleaner(repo, time.Hour)
+	// 送信待ちの通知を1分ごとに送る。失敗したものは時間を空けて再送する
+	model.StartNotificationSender(repo, time.Minute)
+	// トレンドのスコアを定期的に計算し直す
+	model.StartTrendingRefresher(repo, model.TrendingRefreshInterval())
+
 	// ルーティング
 	api.RegisterHandlersWithBaseURL(e, h, "/api/v1")
 
-	e.Logger.Fatal(e.Start(":8080"))
+	go func() {
+		if err := e.Start(":8080"); err != nil && err != http.ErrServerClosed {
+			e.Logger.Fatal(err)
+		}
+	}()
+
+	// SIGINT・SIGTERMを受けたら処理中のリクエストを終えてから、溜まっている閲覧の記録を書き込んで終了する
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
+	<-quit
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := e.Shutdown(shutdownCtx); err != nil {
+		logger.Println("Shutdown Error: ", err)
+	}
+	views.Close()
 }
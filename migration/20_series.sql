@@ -0,0 +1,23 @@
+-- +goose Up
+
+-- seriesテーブル (連載記事のまとまり)
+CREATE TABLE `series` (
+    `id` CHAR(36) NOT NULL,
+    `title` VARCHAR(255) NOT NULL,
+    `description` TEXT,
+    `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `updated_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- series_articlesテーブル (記事は1つのシリーズにのみ所属し、positionで順序付けされる)
+CREATE TABLE `series_articles` (
+    `series_id` CHAR(36) NOT NULL,
+    `article_id` CHAR(36) NOT NULL,
+    `position` INT NOT NULL,
+    PRIMARY KEY (`series_id`,`article_id`),
+    UNIQUE KEY `uq_series_articles_article_id` (`article_id`),
+    UNIQUE KEY `uq_series_articles_position` (`series_id`,`position`),
+    CONSTRAINT `fk_series_articles_series` FOREIGN KEY (`series_id`) REFERENCES `series`(`id`) ON DELETE CASCADE,
+    CONSTRAINT `fk_series_articles_articles` FOREIGN KEY (`article_id`) REFERENCES `articles`(`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
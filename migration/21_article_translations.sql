@@ -0,0 +1,20 @@
+-- +goose Up
+
+-- 元記事の言語 (既存の記事は日本語)
+ALTER TABLE `articles` ADD COLUMN `lang` VARCHAR(10) NOT NULL DEFAULT 'ja';
+
+-- article_translationsテーブル (元記事に紐づく言語ごとの翻訳)
+CREATE TABLE `article_translations` (
+    `article_id` CHAR(36) NOT NULL,
+    `lang` VARCHAR(10) NOT NULL,
+    `title` VARCHAR(255) NOT NULL,
+    `content` TEXT NOT NULL,
+    `excerpt` TEXT,
+    `slug` VARCHAR(255) NOT NULL,
+    `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `updated_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (`article_id`,`lang`),
+    UNIQUE KEY `uq_article_translations_lang_slug` (`lang`,`slug`),
+    KEY `idx_article_translations_lang` (`lang`),
+    CONSTRAINT `fk_article_translations_articles` FOREIGN KEY (`article_id`) REFERENCES `articles`(`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,11 @@
+-- +goose Up
+
+-- 削除された記事・コメント・タグはdeleted_atを設定してゴミ箱に移し、保持期間を過ぎたら完全に削除する
+ALTER TABLE `articles` ADD COLUMN `deleted_at` TIMESTAMP NULL DEFAULT NULL,
+    ADD INDEX `idx_articles_deleted_at` (`deleted_at`);
+
+ALTER TABLE `comments` ADD COLUMN `deleted_at` TIMESTAMP NULL DEFAULT NULL,
+    ADD INDEX `idx_comments_deleted_at` (`deleted_at`);
+
+ALTER TABLE `tags` ADD COLUMN `deleted_at` TIMESTAMP NULL DEFAULT NULL,
+    ADD INDEX `idx_tags_deleted_at` (`deleted_at`);
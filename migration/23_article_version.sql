@@ -0,0 +1,4 @@
+-- +goose Up
+
+-- 記事を編集するたびに増える版数。ETagとIf-Matchによる楽観的排他制御に使う
+ALTER TABLE `articles` ADD COLUMN `version` INT NOT NULL DEFAULT 1;
@@ -0,0 +1,12 @@
+-- +goose Up
+
+-- いいね数・コメント数を記事に持たせ、一覧のたびにCOUNT(*)しないようにする
+-- いいね・コメントの追加や削除と同じトランザクションで更新する
+ALTER TABLE `articles` ADD COLUMN `like_count` INT NOT NULL DEFAULT 0,
+    ADD COLUMN `comment_count` INT NOT NULL DEFAULT 0,
+    ADD INDEX `idx_articles_like_count` (`like_count`),
+    ADD INDEX `idx_articles_comment_count` (`comment_count`);
+
+UPDATE `articles` a SET
+    a.`like_count` = (SELECT COUNT(*) FROM `likes` l WHERE l.`article_id` = a.`id`),
+    a.`comment_count` = (SELECT COUNT(*) FROM `comments` c WHERE c.`article_id` = a.`id` AND c.`deleted_at` IS NULL);
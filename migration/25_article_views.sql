@@ -0,0 +1,15 @@
+-- +goose Up
+
+-- 生のアクセス数。ボットや再読み込みも含めてすべて数える
+ALTER TABLE `articles` ADD COLUMN `hit_count` BIGINT NOT NULL DEFAULT 0;
+
+-- 訪問者ごとに最後に閲覧数として数えた日時。同じ訪問者は一定時間内に1回しか数えない
+-- visitor_hashはIPアドレスとUser-AgentのSHA-256で、IPアドレスそのものは保存しない
+CREATE TABLE IF NOT EXISTS `article_views` (
+    `article_id` CHAR(36) NOT NULL,
+    `visitor_hash` CHAR(64) NOT NULL,
+    `counted_at` TIMESTAMP NOT NULL,
+    PRIMARY KEY (`article_id`, `visitor_hash`),
+    KEY `idx_article_views_counted_at` (`counted_at`),
+    CONSTRAINT `fk_article_views_articles` FOREIGN KEY (`article_id`) REFERENCES `articles`(`id`) ON DELETE CASCADE
+);
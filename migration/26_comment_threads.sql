@@ -0,0 +1,8 @@
+-- +goose Up
+
+-- 返信先のコメント。トップレベルのコメントはNULL
+-- depthはトップレベルを0とした深さで、返信を追加するときに最大の深さを超えていないかを調べるために持つ
+ALTER TABLE `comments` ADD COLUMN `parent_id` CHAR(36) NULL DEFAULT NULL,
+    ADD COLUMN `depth` INT NOT NULL DEFAULT 0,
+    ADD INDEX `idx_comments_parent_id` (`parent_id`),
+    ADD CONSTRAINT `fk_comments_parent` FOREIGN KEY (`parent_id`) REFERENCES `comments`(`id`) ON DELETE CASCADE;
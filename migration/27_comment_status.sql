@@ -0,0 +1,7 @@
+-- +goose Up
+
+-- コメントの公開状態 (pending, approved, rejected, spam)。既存のコメントは公開済みとして扱う
+-- moderation_reasonには承認待ちになった理由を残す
+ALTER TABLE `comments` ADD COLUMN `status` VARCHAR(16) NOT NULL DEFAULT 'approved',
+    ADD COLUMN `moderation_reason` VARCHAR(255) NULL DEFAULT NULL,
+    ADD INDEX `idx_comments_status` (`status`);
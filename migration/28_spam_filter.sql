@@ -0,0 +1,36 @@
+-- +goose Up
+
+-- 投稿時のスパムスコア (0〜1)。trained_asは学習に使った分類 (spam, ham) で、判定が変わったときに学習を取り消すために持つ
+ALTER TABLE `comments` ADD COLUMN `spam_score` DOUBLE NULL DEFAULT NULL,
+    ADD COLUMN `trained_as` VARCHAR(8) NULL DEFAULT NULL;
+
+-- ナイーブベイズの学習結果。トークンごとにスパムとそれ以外の文書に現れた回数を持つ
+CREATE TABLE IF NOT EXISTS `spam_tokens` (
+    `token` VARCHAR(64) NOT NULL,
+    `spam_count` INT NOT NULL DEFAULT 0,
+    `ham_count` INT NOT NULL DEFAULT 0,
+    PRIMARY KEY (`token`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
+
+-- 分類ごとの学習した文書の数
+CREATE TABLE IF NOT EXISTS `spam_documents` (
+    `class` VARCHAR(8) NOT NULL,
+    `documents` INT NOT NULL DEFAULT 0,
+    PRIMARY KEY (`class`)
+);
+INSERT INTO `spam_documents` (`class`, `documents`) VALUES ('spam', 0), ('ham', 0);
+
+-- お問い合わせの記録。スパムと判定したものはSlackに送らず、ここにだけ残す
+CREATE TABLE IF NOT EXISTS `contact_messages` (
+    `id` CHAR(36) NOT NULL,
+    `name` VARCHAR(255) NOT NULL,
+    `email` VARCHAR(255) NOT NULL,
+    `message` TEXT NOT NULL,
+    `ipaddress` VARCHAR(500) NULL,
+    `spam_score` DOUBLE NOT NULL DEFAULT 0,
+    `spam_reasons` VARCHAR(255) NULL,
+    `is_spam` BOOLEAN NOT NULL DEFAULT FALSE,
+    `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    KEY `idx_contact_messages_created_at` (`created_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,16 @@
+-- +goose Up
+
+-- 最後に編集した日時。編集されていないコメントはNULL
+ALTER TABLE `comments` ADD COLUMN `edited_at` TIMESTAMP NULL DEFAULT NULL;
+
+-- 編集で置き換えられる前の本文。replaced_atはその本文が置き換えられた日時、edited_byは編集したユーザー
+CREATE TABLE IF NOT EXISTS `comment_revisions` (
+    `id` CHAR(36) NOT NULL,
+    `comment_id` CHAR(36) NOT NULL,
+    `content` TEXT NOT NULL,
+    `replaced_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `edited_by` CHAR(36) NULL,
+    PRIMARY KEY (`id`),
+    KEY `idx_comment_revisions_comment_id` (`comment_id`, `replaced_at`),
+    CONSTRAINT `fk_comment_revisions_comments` FOREIGN KEY (`comment_id`) REFERENCES `comments`(`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,4 @@
+-- +goose Up
+
+-- Markdownの本文から作ったHTML。既存のコメントはNULLで、読み込むときに作る
+ALTER TABLE `comments` ADD COLUMN `content_html` TEXT NULL DEFAULT NULL;
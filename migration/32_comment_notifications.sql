@@ -0,0 +1,33 @@
+-- +goose Up
+
+-- 通知を作成済みのコメント。承認し直しても二重に通知しないために持つ
+ALTER TABLE `comments` ADD COLUMN `notified_at` TIMESTAMP NULL DEFAULT NULL;
+
+-- 送信待ちの通知。送信に失敗した場合はattemptsを増やし、next_attempt_atまで待ってから再送する
+CREATE TABLE IF NOT EXISTS `notifications` (
+    `id` CHAR(36) NOT NULL,
+    `channel` VARCHAR(16) NOT NULL,
+    `recipient` VARCHAR(255) NULL,
+    `subject` VARCHAR(255) NOT NULL,
+    `body` TEXT NOT NULL,
+    `unsubscribe_url` VARCHAR(500) NULL,
+    `attempts` INT NOT NULL DEFAULT 0,
+    `next_attempt_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `last_error` VARCHAR(500) NULL,
+    `sent_at` TIMESTAMP NULL DEFAULT NULL,
+    `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    KEY `idx_notifications_pending` (`sent_at`, `next_attempt_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- コメントへの返信をメールで受け取る購読。unsubscribed_atが入ったものには送らない
+CREATE TABLE IF NOT EXISTS `comment_subscriptions` (
+    `id` CHAR(36) NOT NULL,
+    `comment_id` CHAR(36) NOT NULL,
+    `email` VARCHAR(255) NOT NULL,
+    `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `unsubscribed_at` TIMESTAMP NULL DEFAULT NULL,
+    PRIMARY KEY (`id`),
+    KEY `idx_comment_subscriptions_comment_id` (`comment_id`),
+    CONSTRAINT `fk_comment_subscriptions_comments` FOREIGN KEY (`comment_id`) REFERENCES `comments`(`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
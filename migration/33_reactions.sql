@@ -0,0 +1,33 @@
+-- +goose Up
+
+-- 記事へのリアクション。訪問者ごとに同じ絵文字は1回だけ
+CREATE TABLE IF NOT EXISTS `article_reactions` (
+    `id` CHAR(36) NOT NULL,
+    `article_id` CHAR(36) NOT NULL,
+    `user_id` CHAR(36) NOT NULL,
+    `emoji` VARCHAR(32) NOT NULL,
+    `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uq_article_reactions_article_user_emoji` (`article_id`, `user_id`, `emoji`),
+    KEY `idx_article_reactions_user_id` (`user_id`),
+    CONSTRAINT `fk_article_reactions_articles` FOREIGN KEY (`article_id`) REFERENCES `articles`(`id`) ON DELETE CASCADE,
+    CONSTRAINT `fk_article_reactions_users` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
+
+-- コメントへのリアクション。件数はcomments.reaction_countにも持つ
+CREATE TABLE IF NOT EXISTS `comment_reactions` (
+    `id` CHAR(36) NOT NULL,
+    `comment_id` CHAR(36) NOT NULL,
+    `user_id` CHAR(36) NOT NULL,
+    `emoji` VARCHAR(32) NOT NULL,
+    `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uq_comment_reactions_comment_user_emoji` (`comment_id`, `user_id`, `emoji`),
+    KEY `idx_comment_reactions_user_id` (`user_id`),
+    CONSTRAINT `fk_comment_reactions_comments` FOREIGN KEY (`comment_id`) REFERENCES `comments`(`id`) ON DELETE CASCADE,
+    CONSTRAINT `fk_comment_reactions_users` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
+
+-- コメントへのリアクションの数。「リアクションの多い順」の並べ替えに使う
+ALTER TABLE `comments` ADD COLUMN `reaction_count` INT NOT NULL DEFAULT 0,
+    ADD INDEX `idx_comments_article_reaction_count` (`article_id`, `reaction_count`);
@@ -0,0 +1,22 @@
+-- +goose Up
+
+-- 1時間ごとの閲覧数 (ボットと重複を除いたもの)。トレンドの計算に使い、最も長い集計期間を過ぎたら削除する
+CREATE TABLE IF NOT EXISTS `article_view_hours` (
+    `article_id` CHAR(36) NOT NULL,
+    `hour` DATETIME NOT NULL,
+    `views` INT NOT NULL DEFAULT 0,
+    PRIMARY KEY (`article_id`, `hour`),
+    KEY `idx_article_view_hours_hour` (`hour`),
+    CONSTRAINT `fk_article_view_hours_articles` FOREIGN KEY (`article_id`) REFERENCES `articles`(`id`) ON DELETE CASCADE
+);
+
+-- 集計期間ごとのトレンドのスコア。定期的にまとめて計算し直す
+CREATE TABLE IF NOT EXISTS `article_trending` (
+    `article_id` CHAR(36) NOT NULL,
+    `period` VARCHAR(8) NOT NULL,
+    `score` DOUBLE NOT NULL,
+    `computed_at` TIMESTAMP NOT NULL,
+    PRIMARY KEY (`period`, `article_id`),
+    KEY `idx_article_trending_score` (`period`, `score`),
+    CONSTRAINT `fk_article_trending_articles` FOREIGN KEY (`article_id`) REFERENCES `articles`(`id`) ON DELETE CASCADE
+);
@@ -49,6 +49,16 @@ func (repo *Repository) CreateAnalysis(ctx context.Context, analysis Analysis) e
 	return err
 }
 
+// CreateAnalyses - 複数の記録を1回のINSERTで追加する
+// 1行で7個のプレースホルダーを使うので、呼び出し側でMySQLの上限(65,535)を超えない件数に分ける
+func (repo *Repository) CreateAnalyses(ctx context.Context, analyses []Analysis) error {
+	if len(analyses) == 0 {
+		return nil
+	}
+	_, err := repo.db.NamedExecContext(ctx, "INSERT INTO analysis (id, timestamp, articleId, ipaddress, search_word, api, is_error) VALUES (:id, :timestamp, :articleId, :ipaddress, :search_word, :api, :is_error)", analyses)
+	return err
+}
+
 func (repo *Repository) UpdateAnalysis(ctx context.Context, analysis Analysis) error {
 	_, err := repo.db.NamedExecContext(ctx, "UPDATE analysis SET timestamp = :timestamp, articleId = :articleId, ipaddress = :ipaddress, search_word = :search_word, api = :api, is_error = :is_error WHERE id = :id", analysis)
 	return err
@@ -4,68 +4,139 @@ import (
 	"blog-backend/logger"
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 )
 
 type Article struct {
-	ID         uuid.UUID      `db:"id"`
-	Title      string         `db:"title"`
-	Content    string         `db:"content"`
-	AuthorID   uuid.UUID      `db:"author_id"`
-	CategoryID uuid.UUID      `db:"category_id"`
-	CreatedAt  time.Time      `db:"created_at"`
-	UpdatedAt  time.Time      `db:"updated_at"`
-	ViewCount  sql.NullInt64  `db:"view_count"`
-	ImageURL   sql.NullString `db:"image_url"`
+	ID           uuid.UUID      `db:"id"`
+	Title        string         `db:"title"`
+	Content      string         `db:"content"`
+	AuthorID     uuid.UUID      `db:"author_id"`
+	CategoryID   uuid.UUID      `db:"category_id"`
+	CreatedAt    time.Time      `db:"created_at"`
+	UpdatedAt    time.Time      `db:"updated_at"`
+	ViewCount    sql.NullInt64  `db:"view_count"`
+	ImageURL     sql.NullString `db:"image_url"`
+	Lang         string         `db:"lang"` // 元記事の言語
+	DeletedAt    sql.NullTime   `db:"deleted_at"`
+	Version      int            `db:"version"`       // 編集するたびに増える
+	HitCount     int64          `db:"hit_count"`     // ボットや重複も含めたアクセス数
+	LikeCount    int            `db:"like_count"`    // いいねの追加・削除と同じトランザクションで更新する
+	CommentCount int            `db:"comment_count"` // ゴミ箱にあるコメントは含めない
 }
 
+// ErrVersionConflict - 更新・削除しようとした記事の版数が、すでに他の編集で変わっていた
+var ErrVersionConflict = errors.New("article version conflict")
+
 func (repo *Repository) GetArticleByID(ctx context.Context, id uuid.UUID) (Article, error) {
+	key := cacheKeyArticle(id.String())
+	if cached, ok := repo.cache.Get(key); ok {
+		return cached.(Article), nil
+	}
 	var article Article
-	err := repo.db.GetContext(ctx, &article, "SELECT * FROM articles WHERE id = ?", id)
+	err := repo.db.GetContext(ctx, &article, "SELECT * FROM articles WHERE id = ? AND deleted_at IS NULL", id)
+	if err == nil {
+		repo.cache.Set(key, article)
+	}
 	return article, err
 }
 
 func (repo *Repository) GetArticles(ctx context.Context, limitNumber *int) ([]Article, error) {
 	var articles []Article
 	if limitNumber != nil {
-		err := repo.db.SelectContext(ctx, &articles, "SELECT * FROM articles LIMIT ?", limitNumber)
+		err := repo.db.SelectContext(ctx, &articles, "SELECT * FROM articles WHERE deleted_at IS NULL LIMIT ?", limitNumber)
 		return articles, err
 	} else {
-		err := repo.db.SelectContext(ctx, &articles, "SELECT * FROM articles")
+		err := repo.db.SelectContext(ctx, &articles, "SELECT * FROM articles WHERE deleted_at IS NULL")
 		return articles, err
 	}
 }
 
 func (repo *Repository) CreateArticle(ctx context.Context, article Article) (Article, error) {
-	_, err := repo.db.NamedExecContext(ctx, "INSERT INTO articles (id, title, content, author_id, category_id, created_at, updated_at) VALUES (:id, :title, :content, :author_id, :category_id, :created_at, :updated_at)", article)
+	_, err := repo.db.NamedExecContext(ctx, "INSERT INTO articles (id, title, content, author_id, category_id, created_at, updated_at, lang) VALUES (:id, :title, :content, :author_id, :category_id, :created_at, :updated_at, :lang)", article)
 	return article, err
 }
 
+// UpdateArticle - article.Versionが現在の版数と異なる場合はErrVersionConflictを返す
 func (repo *Repository) UpdateArticle(ctx context.Context, article Article) (Article, error) {
-	_, err := repo.db.NamedExecContext(ctx, "UPDATE articles SET title = :title, content = :content, author_id = :author_id, category_id = :category_id, updated_at = :updated_at WHERE id = :id", article)
+	return repo.UpdateArticleWithTags(ctx, article, nil)
+}
+
+// UpdateArticleWithTags - 記事とタグの付け替えを1つのトランザクションで行う。tagIDsがnilの場合はタグを変更しない
+// article.Versionが現在の版数と異なる場合はErrVersionConflictを返す。成功すると版数が1つ増える
+func (repo *Repository) UpdateArticleWithTags(ctx context.Context, article Article, tagIDs []uuid.UUID) (Article, error) {
+	tx, err := repo.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return article, err
+	}
+	result, err := tx.NamedExecContext(ctx, "UPDATE articles SET title = :title, content = :content, author_id = :author_id, category_id = :category_id, updated_at = :updated_at, version = version + 1 WHERE id = :id AND version = :version AND deleted_at IS NULL", article)
+	if err != nil {
+		tx.Rollback()
+		return article, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		tx.Rollback()
+		return article, err
+	}
+	if rows == 0 {
+		tx.Rollback()
+		return article, ErrVersionConflict
+	}
+	article.Version++
+	if tagIDs != nil {
+		err = replaceArticleTags(ctx, tx, article.ID, tagIDs)
+		if err != nil {
+			tx.Rollback()
+			return article, err
+		}
+	}
+	err = tx.Commit()
+	repo.cache.Delete(cacheKeyArticle(article.ID.String()))
+	if tagIDs != nil {
+		// タグごとの記事数が変わる
+		repo.cache.Delete(cacheKeyTagList)
+	}
 	return article, err
 }
 
 func (repo *Repository) UpdateArticleImageURL(ctx context.Context, id uuid.UUID, imageURL string) error {
 	_, err := repo.db.ExecContext(ctx, "UPDATE articles SET image_url = ? WHERE id = ?", imageURL, id)
+	repo.cache.Delete(cacheKeyArticle(id.String()))
 	return err
 }
 
-func (repo *Repository) DeleteArticle(ctx context.Context, id uuid.UUID) error {
-	_, err := repo.db.ExecContext(ctx, "DELETE FROM articles WHERE id = ?", id)
-	return err
+// DeleteArticle - 記事をゴミ箱に移す。コメントやいいねは残り、復元すると元に戻る
+// versionが現在の版数と異なる場合はErrVersionConflictを返す
+func (repo *Repository) DeleteArticle(ctx context.Context, id uuid.UUID, version int) error {
+	result, err := repo.db.ExecContext(ctx, "UPDATE articles SET deleted_at = ?, version = version + 1 WHERE id = ? AND version = ? AND deleted_at IS NULL", time.Now(), id, version)
+	if err != nil {
+		return err
+	}
+	// ゴミ箱にある記事はタグごとの記事数に含めない
+	repo.cache.Delete(cacheKeyArticle(id.String()), cacheKeyTagList)
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrVersionConflict
+	}
+	return nil
 }
 
 func (repo *Repository) GetArticlesByCategory(ctx context.Context, category_id uuid.UUID, limitNumber *int) ([]Article, error) {
 	var articles []Article
 	if limitNumber != nil {
-		err := repo.db.SelectContext(ctx, &articles, "SELECT * FROM articles WHERE category_id = ? LIMIT ?", category_id, limitNumber)
+		err := repo.db.SelectContext(ctx, &articles, "SELECT * FROM articles WHERE category_id = ? AND deleted_at IS NULL LIMIT ?", category_id, limitNumber)
 		return articles, err
 	} else {
-		err := repo.db.SelectContext(ctx, &articles, "SELECT * FROM articles WHERE category_id = ?", category_id)
+		err := repo.db.SelectContext(ctx, &articles, "SELECT * FROM articles WHERE category_id = ? AND deleted_at IS NULL", category_id)
 		return articles, err
 	}
 }
@@ -73,10 +144,10 @@ func (repo *Repository) GetArticlesByCategory(ctx context.Context, category_id u
 func (repo *Repository) GetArticlesByAuthor(ctx context.Context, author uuid.UUID, limitNumber *int) ([]Article, error) {
 	var articles []Article
 	if limitNumber != nil {
-		err := repo.db.SelectContext(ctx, &articles, "SELECT * FROM articles WHERE author_id = ? LIMIT ?", author, limitNumber)
+		err := repo.db.SelectContext(ctx, &articles, "SELECT * FROM articles WHERE author_id = ? AND deleted_at IS NULL LIMIT ?", author, limitNumber)
 		return articles, err
 	} else {
-		err := repo.db.SelectContext(ctx, &articles, "SELECT * FROM articles WHERE author_id = ?", author)
+		err := repo.db.SelectContext(ctx, &articles, "SELECT * FROM articles WHERE author_id = ? AND deleted_at IS NULL", author)
 		return articles, err
 	}
 }
@@ -84,182 +155,88 @@ func (repo *Repository) GetArticlesByAuthor(ctx context.Context, author uuid.UUI
 func (repo *Repository) GetArticlesByDate(ctx context.Context, start time.Time, end time.Time, limitNumber *int) ([]Article, error) {
 	var articles []Article
 	if limitNumber != nil {
-		err := repo.db.SelectContext(ctx, &articles, "SELECT * FROM articles WHERE created_at BETWEEN ? AND ? LIMIT ?", start, end, limitNumber)
+		err := repo.db.SelectContext(ctx, &articles, "SELECT * FROM articles WHERE created_at BETWEEN ? AND ? AND deleted_at IS NULL LIMIT ?", start, end, limitNumber)
 		return articles, err
 	} else {
-		err := repo.db.SelectContext(ctx, &articles, "SELECT * FROM articles WHERE created_at BETWEEN ? AND ?", start, end)
+		err := repo.db.SelectContext(ctx, &articles, "SELECT * FROM articles WHERE created_at BETWEEN ? AND ? AND deleted_at IS NULL", start, end)
 		return articles, err
 	}
 }
 
-func (repo *Repository) GetArticlesByCategoryTagSearch(ctx context.Context, category_id *uuid.UUID, tag_id *uuid.UUID, search *string, limitNumber *int, orderby string, order string) ([]Article, error) {
-	if orderby != "created_at" {
-		return nil, fmt.Errorf("orderby must be created_at")
+// ArticleFilter - 記事一覧を取得する際の条件。ゼロ値の項目は条件に含めない
+type ArticleFilter struct {
+	CategoryID uuid.UUID
+	TagID      uuid.UUID
+	Search     *string
+	Lang       string // 指定された言語で読める記事(元記事または翻訳)のみに絞り込む
+	Limit      int
+	OrderBy    string // created_at, view_count, like_count or comment_count
+	Order      string // asc or desc
+}
+
+func (repo *Repository) GetArticlesByFilter(ctx context.Context, filter ArticleFilter) ([]Article, error) {
+	conditions := []string{"deleted_at IS NULL"}
+	args := make([]interface{}, 0)
+	if filter.CategoryID != uuid.Nil {
+		conditions = append(conditions, "category_id = ?")
+		args = append(args, filter.CategoryID)
 	}
-	var articles []Article
-	fmt.Println(*limitNumber != 0)
-	fmt.Println(*category_id != uuid.Nil)
-	fmt.Println(*tag_id != uuid.Nil)
-	fmt.Println(search != nil)
-	fmt.Println("nil conditions:", *limitNumber != 0, *category_id != uuid.Nil, *tag_id != uuid.Nil, search != nil)
-	if *limitNumber != 0 {
-		logger.Println("limitNumber:", *limitNumber)
-		if *category_id != uuid.Nil && *tag_id != uuid.Nil && search != nil {
-			if order == "asc" {
-				logger.Println("condition 1-1")
-				err := repo.db.SelectContext(ctx, &articles, "SELECT * FROM articles WHERE category_id = ? AND id IN (SELECT article_id FROM article_tags WHERE tag_id = ?) AND (title LIKE ? OR content LIKE ?) ORDER BY created_at ASC LIMIT ?", category_id, tag_id, "%"+*search+"%", "%"+*search+"%", limitNumber)
-				return articles, err
-			}
-			logger.Println("condition 1")
-			err := repo.db.SelectContext(ctx, &articles, "SELECT * FROM articles WHERE category_id = ? AND id IN (SELECT article_id FROM article_tags WHERE tag_id = ?) AND (title LIKE ? OR content LIKE ?) ORDER BY created_at DESC LIMIT ?", category_id, tag_id, "%"+*search+"%", "%"+*search+"%", limitNumber)
-			return articles, err
-		} else if *category_id != uuid.Nil && *tag_id != uuid.Nil {
-			if order == "asc" {
-				logger.Println("condition 2-1")
-				err := repo.db.SelectContext(ctx, &articles, "SELECT * FROM articles WHERE category_id = ? AND id IN (SELECT article_id FROM article_tags WHERE tag_id = ?) ORDER BY created_at ASC LIMIT ?", category_id, tag_id, limitNumber)
-				return articles, err
-			}
-			logger.Println("condition 2")
-			err := repo.db.SelectContext(ctx, &articles, "SELECT * FROM articles WHERE category_id = ? AND id IN (SELECT article_id FROM article_tags WHERE tag_id = ?) ORDER BY created_at DESC LIMIT ?", category_id, tag_id, limitNumber)
-			return articles, err
-		} else if *category_id != uuid.Nil && search != nil {
-			if order == "asc" {
-				logger.Println("condition 3-1")
-				err := repo.db.SelectContext(ctx, &articles, "SELECT * FROM articles WHERE category_id = ? AND (title LIKE ? OR content LIKE ?) ORDER BY created_at ASC LIMIT ?", category_id, "%"+*search+"%", "%"+*search+"%", limitNumber)
-				return articles, err
-			}
-			logger.Println("condition 3")
-			err := repo.db.SelectContext(ctx, &articles, "SELECT * FROM articles WHERE category_id = ? AND (title LIKE ? OR content LIKE ?) ORDER BY created_at DESC LIMIT ?", category_id, "%"+*search+"%", "%"+*search+"%", limitNumber)
-			return articles, err
-		} else if *tag_id != uuid.Nil && search != nil {
-			if order == "asc" {
-				logger.Println("condition 4-1")
-				err := repo.db.SelectContext(ctx, &articles, "SELECT * FROM articles WHERE id IN (SELECT article_id FROM article_tags WHERE tag_id = ?) AND (title LIKE ? OR content LIKE ?) ORDER BY created_at ASC LIMIT ?", tag_id, "%"+*search+"%", "%"+*search+"%", limitNumber)
-				return articles, err
-			}
-			logger.Println("condition 4")
-			err := repo.db.SelectContext(ctx, &articles, "SELECT * FROM articles WHERE id IN (SELECT article_id FROM article_tags WHERE tag_id = ?) AND (title LIKE ? OR content LIKE ?) ORDER BY created_at DESC LIMIT ?", tag_id, "%"+*search+"%", "%"+*search+"%", limitNumber)
-			return articles, err
-		} else if *category_id != uuid.Nil {
-			if order == "asc" {
-				logger.Println("condition 5-1")
-				err := repo.db.SelectContext(ctx, &articles, "SELECT * FROM articles WHERE category_id = ? ORDER BY created_at ASC LIMIT ?", category_id, limitNumber)
-				return articles, err
-			}
-			logger.Println("condition 5")
-			err := repo.db.SelectContext(ctx, &articles, "SELECT * FROM articles WHERE category_id = ? ORDER BY created_at DESC LIMIT ?", category_id, limitNumber)
-			return articles, err
-		} else if *tag_id != uuid.Nil {
-			if order == "asc" {
-				logger.Println("condition 6-1")
-				err := repo.db.SelectContext(ctx, &articles, "SELECT * FROM articles WHERE id IN (SELECT article_id FROM article_tags WHERE tag_id = ?) ORDER BY created_at ASC LIMIT ?", tag_id, limitNumber)
-				return articles, err
-			}
-			logger.Println("condition 6")
-			err := repo.db.SelectContext(ctx, &articles, "SELECT * FROM articles WHERE id IN (SELECT article_id FROM article_tags WHERE tag_id = ?) ORDER BY created_at DESC LIMIT ?", tag_id, limitNumber)
-			return articles, err
-		} else if search != nil {
-			if order == "asc" {
-				logger.Println("condition 7-1")
-				err := repo.db.SelectContext(ctx, &articles, "SELECT * FROM articles WHERE title LIKE ? OR content LIKE ? ORDER BY created_at ASC LIMIT ?", "%"+*search+"%", "%"+*search+"%", limitNumber)
-				return articles, err
-			}
-			logger.Println("condition 7")
-			err := repo.db.SelectContext(ctx, &articles, "SELECT * FROM articles WHERE title LIKE ? OR content LIKE ? ORDER BY created_at DESC LIMIT ?", "%"+*search+"%", "%"+*search+"%", limitNumber)
-			return articles, err
-		} else {
-			if order == "asc" {
-				logger.Println("condition 8-1")
-				err := repo.db.SelectContext(ctx, &articles, "SELECT * FROM articles ORDER BY created_at ASC LIMIT ?", limitNumber)
-				return articles, err
-			}
-			logger.Println("condition 8")
-			err := repo.db.SelectContext(ctx, &articles, "SELECT * FROM articles ORDER BY created_at DESC LIMIT ?", limitNumber)
-			logger.Println("err:", err)
-			return articles, err
-		}
+	if filter.TagID != uuid.Nil {
+		conditions = append(conditions, "id IN (SELECT article_id FROM article_tags WHERE tag_id = ?)")
+		args = append(args, filter.TagID)
+	}
+	if filter.Search != nil {
+		conditions = append(conditions, "(title LIKE ? OR content LIKE ?)")
+		args = append(args, "%"+*filter.Search+"%", "%"+*filter.Search+"%")
+	}
+	if filter.Lang != "" {
+		conditions = append(conditions, "(lang = ? OR id IN (SELECT article_id FROM article_translations WHERE lang = ?))")
+		args = append(args, filter.Lang, filter.Lang)
+	}
+
+	query := "SELECT * FROM articles WHERE " + strings.Join(conditions, " AND ")
+
+	// ORDER BYはプレースホルダーが使えないので、許可した値のみを埋め込む
+	switch filter.OrderBy {
+	case "", "created_at":
+		query += " ORDER BY created_at"
+	case "view_count", "like_count", "comment_count":
+		query += " ORDER BY " + filter.OrderBy
+	default:
+		return nil, fmt.Errorf("orderby must be created_at, view_count, like_count or comment_count")
+	}
+	if filter.Order == "asc" {
+		query += " ASC"
 	} else {
-		if *category_id != uuid.Nil && *tag_id != uuid.Nil && search != nil {
-			if order == "asc" {
-				logger.Println("condition 9-1")
-				err := repo.db.SelectContext(ctx, &articles, "SELECT * FROM articles WHERE category_id = ? AND id IN (SELECT article_id FROM article_tags WHERE tag_id = ?) AND (title LIKE ? OR content LIKE ?) ORDER BY created_at ASC", category_id, tag_id, "%"+*search+"%", "%"+*search+"%")
-				return articles, err
-			}
-			logger.Println("condition 9")
-			err := repo.db.SelectContext(ctx, &articles, "SELECT * FROM articles WHERE category_id = ? AND id IN (SELECT article_id FROM article_tags WHERE tag_id = ?) AND (title LIKE ? OR content LIKE ?) ORDER BY created_at DESC", category_id, tag_id, "%"+*search+"%", "%"+*search+"%")
-			return articles, err
-		} else if *category_id != uuid.Nil && *tag_id != uuid.Nil {
-			if order == "asc" {
-				logger.Println("condition 10-1")
-				err := repo.db.SelectContext(ctx, &articles, "SELECT * FROM articles WHERE category_id = ? AND id IN (SELECT article_id FROM article_tags WHERE tag_id = ?) ORDER BY created_at ASC", category_id, tag_id)
-				return articles, err
-			}
-			logger.Println("condition 10")
-			err := repo.db.SelectContext(ctx, &articles, "SELECT * FROM articles WHERE category_id = ? AND id IN (SELECT article_id FROM article_tags WHERE tag_id = ?) ORDER BY created_at DESC", category_id, tag_id)
-			return articles, err
-		}
-		if *category_id != uuid.Nil && search != nil {
-			if order == "asc" {
-				logger.Println("condition 11-1")
-				err := repo.db.SelectContext(ctx, &articles, "SELECT * FROM articles WHERE category_id = ? AND (title LIKE ? OR content LIKE ?) ORDER BY created_at ASC", category_id, "%"+*search+"%", "%"+*search+"%")
-				return articles, err
-			}
-			logger.Println("condition 11")
-			err := repo.db.SelectContext(ctx, &articles, "SELECT * FROM articles WHERE category_id = ? AND (title LIKE ? OR content LIKE ?) ORDER BY created_at DESC", category_id, "%"+*search+"%", "%"+*search+"%")
-			return articles, err
-		}
-		if *tag_id != uuid.Nil && search != nil {
-			if order == "asc" {
-				logger.Println("condition 12-1")
-				err := repo.db.SelectContext(ctx, &articles, "SELECT * FROM articles WHERE id IN (SELECT article_id FROM article_tags WHERE tag_id = ?) AND (title LIKE ? OR content LIKE ?) ORDER BY created_at ASC", tag_id, "%"+*search+"%", "%"+*search+"%")
-				return articles, err
-			}
-			logger.Println("condition 12")
-			err := repo.db.SelectContext(ctx, &articles, "SELECT * FROM articles WHERE id IN (SELECT article_id FROM article_tags WHERE tag_id = ?) AND (title LIKE ? OR content LIKE ?) ORDER BY created_at DESC", tag_id, "%"+*search+"%", "%"+*search+"%")
-			return articles, err
-		}
-		if *category_id != uuid.Nil {
-			if order == "asc" {
-				logger.Println("condition 13-1")
-				err := repo.db.SelectContext(ctx, &articles, "SELECT * FROM articles WHERE category_id = ? ORDER BY created_at ASC", category_id)
-				return articles, err
-			}
-			logger.Println("condition 13")
-			err := repo.db.SelectContext(ctx, &articles, "SELECT * FROM articles WHERE category_id = ? ORDER BY created_at DESC", category_id)
-			return articles, err
-		}
-		if *tag_id != uuid.Nil {
-			if order == "asc" {
-				logger.Println("condition 14-1")
-				err := repo.db.SelectContext(ctx, &articles, "SELECT * FROM articles WHERE id IN (SELECT article_id FROM article_tags WHERE tag_id = ?) ORDER BY created_at ASC", tag_id)
-				return articles, err
-			}
-			logger.Println("condition 14")
-			err := repo.db.SelectContext(ctx, &articles, "SELECT * FROM articles WHERE id IN (SELECT article_id FROM article_tags WHERE tag_id = ?) ORDER BY created_at DESC", tag_id)
-			return articles, err
-		}
-		if search != nil {
-			if order == "asc" {
-				logger.Println("condition 15-1")
-				err := repo.db.SelectContext(ctx, &articles, "SELECT * FROM articles WHERE title LIKE ? OR content LIKE ? ORDER BY created_at ASC", "%"+*search+"%", "%"+*search+"%")
-				return articles, err
-			}
-			logger.Println("condition 15")
-			err := repo.db.SelectContext(ctx, &articles, "SELECT * FROM articles WHERE title LIKE ? OR content LIKE ? ORDER BY created_at DESC", "%"+*search+"%", "%"+*search+"%")
-			return articles, err
-		}
-		if order == "asc" {
-			logger.Println("condition 16-1")
-			err := repo.db.SelectContext(ctx, &articles, "SELECT * FROM articles ORDER BY created_at ASC")
-			return articles, err
-		}
-		err := repo.db.SelectContext(ctx, &articles, "SELECT * FROM articles ORDER BY created_at DESC")
-		return articles, err
+		query += " DESC"
 	}
+
+	if filter.Limit != 0 {
+		query += " LIMIT ?"
+		args = append(args, filter.Limit)
+	}
+
+	var articles []Article
+	err := repo.db.SelectContext(ctx, &articles, query, args...)
+	return articles, err
+}
+
+func (repo *Repository) GetArticlesByCategoryTagSearch(ctx context.Context, category_id *uuid.UUID, tag_id *uuid.UUID, search *string, limitNumber *int, orderby string, order string) ([]Article, error) {
+	return repo.GetArticlesByFilter(ctx, ArticleFilter{
+		CategoryID: *category_id,
+		TagID:      *tag_id,
+		Search:     search,
+		Limit:      *limitNumber,
+		OrderBy:    orderby,
+		Order:      order,
+	})
 }
 
 func (repo *Repository) SaveViewCount(ctx context.Context, id uuid.UUID) error {
 	_, err := repo.db.ExecContext(ctx, "UPDATE articles SET view_count = view_count + 1 WHERE id = ?", id)
 	logger.Println("view count error:", err)
+	if err == nil {
+		repo.addCachedViewCount(id, 1)
+	}
 	return err
 }
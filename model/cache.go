@@ -0,0 +1,159 @@
+package model
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// キャッシュのキー
+const (
+	cacheKeyTagList      = "tags:list"
+	cacheKeyCategoryList = "categories:list"
+)
+
+func cacheKeyArticle(id string) string {
+	return "article:" + id
+}
+
+// Cache - Repositoryの読み込み結果を保持する、件数上限付きのLRUキャッシュ
+// 各項目はTTLを過ぎると期限切れになる。書き込みのたびに関係するキーを削除する
+type Cache struct {
+	mu       sync.Mutex
+	enabled  bool
+	capacity int
+	ttl      time.Duration
+	items    map[string]*list.Element
+	order    *list.List // 先頭が最近使われた項目
+
+	hits      int64
+	misses    int64
+	evictions int64
+}
+
+type cacheEntry struct {
+	key       string
+	value     interface{}
+	expiresAt time.Time
+}
+
+// CacheStats - キャッシュのヒット率などの統計
+type CacheStats struct {
+	Enabled   bool
+	Size      int
+	Capacity  int
+	TTL       time.Duration
+	Hits      int64
+	Misses    int64
+	Evictions int64
+}
+
+func NewCache(enabled bool, capacity int, ttl time.Duration) *Cache {
+	if capacity <= 0 {
+		enabled = false
+	}
+	return &Cache{
+		enabled:  enabled,
+		capacity: capacity,
+		ttl:      ttl,
+		items:    make(map[string]*list.Element),
+		order:    list.New(),
+	}
+}
+
+// NewCacheFromEnv - CACHE_ENABLED=falseでキャッシュを無効にできる (デバッグ用)
+func NewCacheFromEnv() *Cache {
+	enabled := getEnv("CACHE_ENABLED", "true") != "false"
+	capacity := getEnvInt("CACHE_SIZE", 1000)
+	ttl := time.Duration(getEnvInt("CACHE_TTL_SECONDS", 60)) * time.Second
+	return NewCache(enabled, capacity, ttl)
+}
+
+func (c *Cache) Get(key string) (interface{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.enabled {
+		return nil, false
+	}
+	elem, ok := c.items[key]
+	if !ok {
+		c.misses++
+		return nil, false
+	}
+	entry := elem.Value.(*cacheEntry)
+	if time.Now().After(entry.expiresAt) {
+		c.removeElement(elem)
+		c.misses++
+		return nil, false
+	}
+	c.order.MoveToFront(elem)
+	c.hits++
+	return entry.value, true
+}
+
+func (c *Cache) Set(key string, value interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.enabled {
+		return
+	}
+	if elem, ok := c.items[key]; ok {
+		entry := elem.Value.(*cacheEntry)
+		entry.value = value
+		entry.expiresAt = time.Now().Add(c.ttl)
+		c.order.MoveToFront(elem)
+		return
+	}
+	c.items[key] = c.order.PushFront(&cacheEntry{key: key, value: value, expiresAt: time.Now().Add(c.ttl)})
+	for c.order.Len() > c.capacity {
+		c.removeElement(c.order.Back())
+		c.evictions++
+	}
+}
+
+// Update - キャッシュにある項目だけをfで書き換える。期限は延ばさない
+func (c *Cache) Update(key string, f func(value interface{}) interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.items[key]; ok {
+		entry := elem.Value.(*cacheEntry)
+		entry.value = f(entry.value)
+	}
+}
+
+func (c *Cache) Delete(keys ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, key := range keys {
+		if elem, ok := c.items[key]; ok {
+			c.removeElement(elem)
+		}
+	}
+}
+
+// Clear - すべての項目を削除する。どのキーに影響するか分からない書き込みのあとに使う
+func (c *Cache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.items = make(map[string]*list.Element)
+	c.order.Init()
+}
+
+func (c *Cache) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return CacheStats{
+		Enabled:   c.enabled,
+		Size:      c.order.Len(),
+		Capacity:  c.capacity,
+		TTL:       c.ttl,
+		Hits:      c.hits,
+		Misses:    c.misses,
+		Evictions: c.evictions,
+	}
+}
+
+func (c *Cache) removeElement(elem *list.Element) {
+	c.order.Remove(elem)
+	delete(c.items, elem.Value.(*cacheEntry).key)
+}
@@ -0,0 +1,98 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCacheGetSet(t *testing.T) {
+	c := NewCache(true, 10, time.Minute)
+	_, ok := c.Get("a")
+	assert.False(t, ok)
+
+	c.Set("a", 1)
+	value, ok := c.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, value)
+
+	c.Set("a", 2)
+	value, _ = c.Get("a")
+	assert.Equal(t, 2, value)
+
+	stats := c.Stats()
+	assert.Equal(t, int64(2), stats.Hits)
+	assert.Equal(t, int64(1), stats.Misses)
+	assert.Equal(t, 1, stats.Size)
+}
+
+func TestCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	c := NewCache(true, 2, time.Minute)
+	c.Set("a", 1)
+	c.Set("b", 2)
+	// aを使うと、次に追い出されるのはbになる
+	_, ok := c.Get("a")
+	assert.True(t, ok)
+	c.Set("c", 3)
+
+	_, ok = c.Get("b")
+	assert.False(t, ok)
+	_, ok = c.Get("a")
+	assert.True(t, ok)
+	_, ok = c.Get("c")
+	assert.True(t, ok)
+	assert.Equal(t, int64(1), c.Stats().Evictions)
+	assert.Equal(t, 2, c.Stats().Size)
+}
+
+func TestCacheExpires(t *testing.T) {
+	c := NewCache(true, 10, time.Millisecond)
+	c.Set("a", 1)
+	time.Sleep(5 * time.Millisecond)
+	_, ok := c.Get("a")
+	assert.False(t, ok)
+	assert.Equal(t, 0, c.Stats().Size)
+}
+
+func TestCacheDeleteAndClear(t *testing.T) {
+	c := NewCache(true, 10, time.Minute)
+	c.Set("a", 1)
+	c.Set("b", 2)
+	c.Set("c", 3)
+
+	c.Delete("a", "b", "missing")
+	_, ok := c.Get("a")
+	assert.False(t, ok)
+	_, ok = c.Get("c")
+	assert.True(t, ok)
+
+	c.Clear()
+	_, ok = c.Get("c")
+	assert.False(t, ok)
+	assert.Equal(t, 0, c.Stats().Size)
+}
+
+func TestCacheUpdate(t *testing.T) {
+	c := NewCache(true, 10, time.Minute)
+	increment := func(value interface{}) interface{} { return value.(int) + 1 }
+
+	// キャッシュにない項目は作らない
+	c.Update("a", increment)
+	_, ok := c.Get("a")
+	assert.False(t, ok)
+
+	c.Set("a", 1)
+	c.Update("a", increment)
+	value, _ := c.Get("a")
+	assert.Equal(t, 2, value)
+}
+
+func TestCacheDisabled(t *testing.T) {
+	for _, c := range []*Cache{NewCache(false, 10, time.Minute), NewCache(true, 0, time.Minute)} {
+		c.Set("a", 1)
+		_, ok := c.Get("a")
+		assert.False(t, ok)
+		assert.False(t, c.Stats().Enabled)
+	}
+}
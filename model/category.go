@@ -17,23 +17,32 @@ func (r *Repository) GetCategories(ctx context.Context, limitNumber *int) ([]Cat
 		err := r.db.SelectContext(ctx, &categories, "SELECT * FROM categories LIMIT ?", limitNumber)
 		return categories, err
 	} else {
+		if cached, ok := r.cache.Get(cacheKeyCategoryList); ok {
+			return append([]Category(nil), cached.([]Category)...), nil
+		}
 		err := r.db.SelectContext(ctx, &categories, "SELECT * FROM categories")
+		if err == nil {
+			r.cache.Set(cacheKeyCategoryList, append([]Category(nil), categories...))
+		}
 		return categories, err
 	}
 }
 
 func (r *Repository) CreateCategory(ctx context.Context, category Category) error {
 	_, err := r.db.NamedExecContext(ctx, "INSERT INTO categories (id, name) VALUES (:id, :name)", category)
+	r.cache.Delete(cacheKeyCategoryList)
 	return err
 }
 
 func (r *Repository) UpdateCategory(ctx context.Context, category Category) error {
 	_, err := r.db.NamedExecContext(ctx, "UPDATE categories SET name = :name WHERE id = :id", category)
+	r.cache.Delete(cacheKeyCategoryList)
 	return err
 }
 
 func (r *Repository) DeleteCategory(ctx context.Context, id uuid.UUID) error {
 	_, err := r.db.ExecContext(ctx, "DELETE FROM categories WHERE id = ?", id)
+	r.cache.Delete(cacheKeyCategoryList)
 	return err
 }
 
@@ -3,6 +3,8 @@ package model
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"sort"
 	"time"
 
 	"github.com/google/uuid"
@@ -15,62 +17,381 @@ type Comment struct {
 	Author    sql.NullString `db:"username"` // このフィールドはDBには存在しない。Userテーブルから取得してくる
 	Content   string         `db:"content"`
 	CreatedAt time.Time      `db:"created_at"`
+	DeletedAt sql.NullTime   `db:"deleted_at" json:"-"`
+	ParentID  uuid.NullUUID  `db:"parent_id"` // 返信先のコメント。トップレベルのコメントは無効
+	Depth     int            `db:"depth"`     // トップレベルのコメントが0
+	Status    string         `db:"status"`    // CommentStatusPendingなど。公開されるのはCommentStatusApprovedだけ
+
+	ModerationReason sql.NullString  `db:"moderation_reason"` // 承認待ちになった理由
+	SpamScore        sql.NullFloat64 `db:"spam_score"`
+	TrainedAs        sql.NullString  `db:"trained_as"`   // スパムフィルターの学習に使った分類
+	EditedAt         sql.NullTime    `db:"edited_at"`    // 最後に編集した日時。CreatedAtは最初に投稿した日時のまま
+	ContentHTML      sql.NullString  `db:"content_html"` // Contentから作ったHTML。古いコメントは無効
+	ReactionCount    int             `db:"reaction_count"`
+	NotifiedAt       sql.NullTime    `db:"notified_at"` // 承認の通知を作成した日時。承認し直しても二重に通知しない
+	ReplyCount       int             `db:"-"`           // 公開済みの返信の数(すべての深さ)。GetCommentPageだけが設定する
+}
+
+// HTML - 表示用のHTML。保存されていない古いコメントはここで作る
+func (comment Comment) HTML() string {
+	if comment.ContentHTML.Valid {
+		return comment.ContentHTML.String
+	}
+	return RenderCommentMarkdown(comment.Content)
+}
+
+// CommentRevision - 編集で置き換えられる前のコメントの本文 (Markdown)
+type CommentRevision struct {
+	ID         uuid.UUID     `db:"id"`
+	CommentID  uuid.UUID     `db:"comment_id"`
+	Content    string        `db:"content"`
+	ReplacedAt time.Time     `db:"replaced_at"`
+	EditedBy   uuid.NullUUID `db:"edited_by"`
+}
+
+// 返信を追加できない場合のエラー
+var (
+	ErrParentCommentNotFound = errors.New("parent comment not found")
+	ErrCommentTooDeep        = errors.New("comment reply depth exceeds the maximum")
+)
+
+// DeletedCommentContent - 返信が残っている削除済みのコメントの代わりに表示する本文
+const DeletedCommentContent = "[deleted]"
+
+// HiddenCommentContent - 公開済みの返信が残っている、公開されていないコメントの代わりに表示する本文
+const HiddenCommentContent = "[hidden]"
+
+// CommentMaxDepth - 返信できる最大の深さ。0の場合は返信できない
+func CommentMaxDepth() int {
+	return getEnvInt("COMMENT_MAX_DEPTH", 3)
 }
 
+// CommentEditWindow - 投稿者がコメントを編集できる、投稿してからの期間
+func CommentEditWindow() time.Duration {
+	return time.Duration(getEnvInt("COMMENT_EDIT_WINDOW_MINUTES", 15)) * time.Minute
+}
+
+// ゴミ箱にあるコメント、ゴミ箱にある記事のコメント、公開されていないコメントは取得しない
+const commentSelect = "SELECT c.*, u.username FROM comments c JOIN users u ON c.author_id = u.id JOIN articles a ON c.article_id = a.id AND a.deleted_at IS NULL WHERE c.deleted_at IS NULL AND c.status = 'approved'"
+
 func (repo *Repository) GetCommentByID(ctx context.Context, id uuid.UUID) (Comment, error) {
 	var comment Comment
-	err := repo.db.GetContext(ctx, &comment, "SELECT c.*, u.username FROM comments c JOIN users u ON c.author_id = u.id WHERE c.id = ?", id)
+	err := repo.db.GetContext(ctx, &comment, commentSelect+" AND c.id = ?", id)
+	return comment, err
+}
+
+// GetCommentByIDAnyStatus - 公開されていないコメントも含めて、ゴミ箱にないコメントを取得する
+func (repo *Repository) GetCommentByIDAnyStatus(ctx context.Context, id uuid.UUID) (Comment, error) {
+	var comment Comment
+	err := repo.db.GetContext(ctx, &comment, "SELECT c.*, u.username FROM comments c JOIN users u ON c.author_id = u.id WHERE c.deleted_at IS NULL AND c.id = ?", id)
 	return comment, err
 }
 
 func (repo *Repository) GetComments(ctx context.Context, limitNumber *int) ([]Comment, error) {
 	var comments []Comment
 	if limitNumber != nil {
-		err := repo.db.SelectContext(ctx, &comments, "SELECT c.*, u.username FROM comments c JOIN users u ON c.author_id = u.id LIMIT ?", limitNumber)
+		err := repo.db.SelectContext(ctx, &comments, commentSelect+" LIMIT ?", limitNumber)
 		return comments, err
 	} else {
-		err := repo.db.SelectContext(ctx, &comments, "SELECT c.*, u.username FROM comments c JOIN users u ON c.author_id = u.id")
+		err := repo.db.SelectContext(ctx, &comments, commentSelect)
 		return comments, err
 	}
 }
 
+// CreateComment - コメントを追加し、公開する場合は同じトランザクションで記事のコメント数を増やす
+// Statusが空の場合は公開済みとして追加する
 func (repo *Repository) CreateComment(ctx context.Context, comment Comment) error {
-	_, err := repo.db.NamedExecContext(ctx, "INSERT INTO comments (id, article_id, author_id, content, created_at) VALUES (:id, :article_id, :author_id, :content, :created_at)", comment)
+	if comment.Status == "" {
+		comment.Status = CommentStatusApproved
+	}
+	comment.ContentHTML = sql.NullString{String: RenderCommentMarkdown(comment.Content), Valid: true}
+	tx, err := repo.db.BeginTxx(ctx, nil)
 	if err != nil {
 		return err
 	}
-	_, err = repo.db.ExecContext(ctx, "UPDATE users SET username = ? WHERE id = ?", comment.Author, comment.AuthorID)
+	if comment.ParentID.Valid {
+		// 返信先は同じ記事の公開されていてゴミ箱にないコメントに限る
+		var parent Comment
+		err = tx.GetContext(ctx, &parent, "SELECT * FROM comments WHERE id = ? AND deleted_at IS NULL AND status = ?", comment.ParentID.UUID, CommentStatusApproved)
+		if err == sql.ErrNoRows || (err == nil && parent.ArticleID != comment.ArticleID) {
+			tx.Rollback()
+			return ErrParentCommentNotFound
+		}
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+		if parent.Depth+1 > CommentMaxDepth() {
+			tx.Rollback()
+			return ErrCommentTooDeep
+		}
+		comment.Depth = parent.Depth + 1
+	} else {
+		comment.Depth = 0
+	}
+	_, err = tx.NamedExecContext(ctx, "INSERT INTO comments (id, article_id, author_id, content, created_at, parent_id, depth, status, moderation_reason, spam_score, content_html) VALUES (:id, :article_id, :author_id, :content, :created_at, :parent_id, :depth, :status, :moderation_reason, :spam_score, :content_html)", comment)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if comment.Status == CommentStatusApproved {
+		err = addArticleCounter(ctx, tx, "comment_count", comment.ArticleID, 1)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+		// 保留したコメントは承認したときに通知する
+		err = enqueueCommentNotifications(ctx, tx, comment)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	_, err = tx.ExecContext(ctx, "UPDATE users SET username = ? WHERE id = ?", comment.Author, comment.AuthorID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	err = tx.Commit()
+	repo.cache.Delete(cacheKeyArticle(comment.ArticleID.String()))
 	return err
 }
 
-func (repo *Repository) UpdateComment(ctx context.Context, comment Comment) error {
-	_, err := repo.db.NamedExecContext(ctx, "UPDATE comments SET article_id = :article_id, author_id = :author_id, content = :content, created_at = :created_at WHERE id = :id", comment)
+// UpdateComment - コメントの本文と、編集後に判定し直した状態を書き換える。記事・投稿者・投稿日時は変えない
+// 書き換える前の本文は同じトランザクションでcomment_revisionsに残す。本文が変わらない場合は何もしない
+// 公開済みのコメントが公開されなくなった場合は、同じトランザクションで記事のコメント数を減らす
+func (repo *Repository) UpdateComment(ctx context.Context, comment Comment, editedBy uuid.NullUUID) error {
+	tx, err := repo.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	var current Comment
+	err = tx.GetContext(ctx, &current, "SELECT article_id, content, status, deleted_at FROM comments WHERE id = ? FOR UPDATE", comment.ID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if current.Content == comment.Content {
+		return tx.Rollback()
+	}
+	now := time.Now()
+	_, err = tx.NamedExecContext(ctx, "INSERT INTO comment_revisions (id, comment_id, content, replaced_at, edited_by) VALUES (:id, :comment_id, :content, :replaced_at, :edited_by)", CommentRevision{
+		ID:         uuid.New(),
+		CommentID:  comment.ID,
+		Content:    current.Content,
+		ReplacedAt: now,
+		EditedBy:   editedBy,
+	})
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	_, err = tx.ExecContext(ctx, "UPDATE comments SET content = ?, content_html = ?, edited_at = ?, status = ?, moderation_reason = ?, spam_score = ? WHERE id = ?", comment.Content, RenderCommentMarkdown(comment.Content), now, comment.Status, comment.ModerationReason, comment.SpamScore, comment.ID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	unpublished := current.Status == CommentStatusApproved && comment.Status != CommentStatusApproved && !current.DeletedAt.Valid
+	if unpublished {
+		err = addArticleCounter(ctx, tx, "comment_count", current.ArticleID, -1)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	err = tx.Commit()
+	if unpublished {
+		repo.cache.Delete(cacheKeyArticle(current.ArticleID.String()))
+	}
 	return err
 }
 
+// GetCommentRevisions - コメントの以前の本文を古い順に返す
+func (repo *Repository) GetCommentRevisions(ctx context.Context, commentID uuid.UUID) ([]CommentRevision, error) {
+	var revisions []CommentRevision
+	err := repo.db.SelectContext(ctx, &revisions, "SELECT * FROM comment_revisions WHERE comment_id = ? ORDER BY replaced_at ASC", commentID)
+	return revisions, err
+}
+
+// DeleteComment - コメントをゴミ箱に移し、同じトランザクションで記事のコメント数を減らす
 func (repo *Repository) DeleteComment(ctx context.Context, id uuid.UUID) error {
-	_, err := repo.db.ExecContext(ctx, "DELETE FROM comments WHERE id = ?", id)
+	_, err := repo.setCommentDeletedAt(ctx, id, sql.NullTime{Time: time.Now(), Valid: true})
 	return err
 }
 
+// setCommentDeletedAt - コメントをゴミ箱に移す(deletedAtが有効)か、ゴミ箱から戻す(無効)
+// 状態が変わった場合は公開済みのコメントならコメント数も増減し、trueを返す
+func (repo *Repository) setCommentDeletedAt(ctx context.Context, id uuid.UUID, deletedAt sql.NullTime) (bool, error) {
+	tx, err := repo.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	var comment Comment
+	query := "SELECT * FROM comments WHERE id = ? AND deleted_at IS NULL FOR UPDATE"
+	delta := -1
+	if !deletedAt.Valid {
+		query = "SELECT * FROM comments WHERE id = ? AND deleted_at IS NOT NULL FOR UPDATE"
+		delta = 1
+	}
+	err = tx.GetContext(ctx, &comment, query, id)
+	if err == sql.ErrNoRows {
+		tx.Rollback()
+		return false, nil
+	}
+	if err != nil {
+		tx.Rollback()
+		return false, err
+	}
+	_, err = tx.ExecContext(ctx, "UPDATE comments SET deleted_at = ? WHERE id = ?", deletedAt, id)
+	if err != nil {
+		tx.Rollback()
+		return false, err
+	}
+	if comment.Status == CommentStatusApproved {
+		err = addArticleCounter(ctx, tx, "comment_count", comment.ArticleID, delta)
+		if err != nil {
+			tx.Rollback()
+			return false, err
+		}
+	}
+	err = tx.Commit()
+	repo.cache.Delete(cacheKeyArticle(comment.ArticleID.String()))
+	return err == nil, err
+}
+
 func (repo *Repository) GetCommentsByArticle(ctx context.Context, article_id uuid.UUID, limitNumber *int) ([]Comment, error) {
 	var comments []Comment
 	if limitNumber != nil {
-		err := repo.db.SelectContext(ctx, &comments, "SELECT c.*, u.username FROM comments c JOIN users u ON c.author_id = u.id WHERE article_id = ? LIMIT ?", article_id, limitNumber)
+		err := repo.db.SelectContext(ctx, &comments, commentSelect+" AND c.article_id = ? LIMIT ?", article_id, limitNumber)
 		return comments, err
 	} else {
-		err := repo.db.SelectContext(ctx, &comments, "SELECT c.*, u.username FROM comments c JOIN users u ON c.author_id = u.id WHERE article_id = ?", article_id)
+		err := repo.db.SelectContext(ctx, &comments, commentSelect+" AND c.article_id = ?", article_id)
 		return comments, err
 	}
 }
 
+// コメントの並べ替え。返信は常に投稿順で、並べ替えるのはトップレベルのスレッドの順
+const (
+	CommentSortOldest    = "oldest"
+	CommentSortNewest    = "newest"
+	CommentSortReactions = "reactions" // リアクションの多い順、同じ数なら投稿順
+)
+
+// CommentPage - 記事のコメントの1ページ分
+type CommentPage struct {
+	Comments []Comment // スレッドの順(親の直後にその返信)
+	Total    int       // ゴミ箱にない公開済みのコメントの数
+	Threads  int       // ページに分ける前のトップレベルのスレッドの数
+}
+
+// GetCommentPage - 記事の公開済みのコメントを、トップレベルのスレッド単位でページに分けて返す
+// ゴミ箱にあるコメントや公開されていないコメントは、公開済みの返信が残っている場合だけ本文と投稿者を伏せて残す
+// 1記事のコメントは多くないため、まとめて取得してからスレッドを組み立てる
+func (repo *Repository) GetCommentPage(ctx context.Context, articleID uuid.UUID, sortBy string, page int, perPage int) (CommentPage, error) {
+	var comments []Comment
+	err := repo.db.SelectContext(ctx, &comments, "SELECT c.*, u.username FROM comments c JOIN users u ON c.author_id = u.id JOIN articles a ON c.article_id = a.id AND a.deleted_at IS NULL WHERE c.article_id = ? ORDER BY c.created_at ASC, c.id ASC", articleID)
+	if err != nil {
+		return CommentPage{}, err
+	}
+	return buildCommentPage(comments, sortBy, page, perPage), nil
+}
+
+// buildCommentPage - 投稿順に並んだ記事のすべてのコメントからスレッドを組み立て、1ページ分を返す
+func buildCommentPage(comments []Comment, sortBy string, page int, perPage int) CommentPage {
+	// published - ゴミ箱になく、公開済みのコメント。それ以外は伏せて表示する
+	published := func(comment Comment) bool {
+		return !comment.DeletedAt.Valid && comment.Status == CommentStatusApproved
+	}
+	children := make(map[uuid.UUID][]Comment)
+	roots := make([]Comment, 0)
+	result := CommentPage{}
+	for _, comment := range comments {
+		if published(comment) {
+			result.Total++
+		}
+		if comment.ParentID.Valid {
+			children[comment.ParentID.UUID] = append(children[comment.ParentID.UUID], comment)
+		} else {
+			roots = append(roots, comment)
+		}
+	}
+
+	// replies - 公開済みの子孫の数。伏せるコメントはこれが0なら表示しない
+	memo := make(map[uuid.UUID]int)
+	var replies func(comment Comment) int
+	replies = func(comment Comment) int {
+		if n, ok := memo[comment.ID]; ok {
+			return n
+		}
+		n := 0
+		for _, child := range children[comment.ID] {
+			n += replies(child)
+			if published(child) {
+				n++
+			}
+		}
+		memo[comment.ID] = n
+		return n
+	}
+	visible := func(comment Comment) bool {
+		return published(comment) || replies(comment) > 0
+	}
+
+	threads := make([]Comment, 0, len(roots))
+	for _, root := range roots {
+		if visible(root) {
+			threads = append(threads, root)
+		}
+	}
+	switch sortBy {
+	case CommentSortNewest:
+		sort.SliceStable(threads, func(i, j int) bool { return threads[i].CreatedAt.After(threads[j].CreatedAt) })
+	case CommentSortReactions:
+		sort.SliceStable(threads, func(i, j int) bool { return threads[i].ReactionCount > threads[j].ReactionCount })
+	}
+	result.Threads = len(threads)
+	// pageが非常に大きくても掛け算があふれないよう、範囲外のページは先に空にする
+	start := len(threads)
+	if page-1 <= len(threads)/perPage {
+		start = min((page-1)*perPage, len(threads))
+	}
+	end := min(start+perPage, len(threads))
+
+	result.Comments = make([]Comment, 0)
+	var walk func(list []Comment)
+	walk = func(list []Comment) {
+		for _, comment := range list {
+			if !visible(comment) {
+				continue
+			}
+			comment.ReplyCount = replies(comment)
+			if !published(comment) {
+				content := HiddenCommentContent
+				if comment.DeletedAt.Valid {
+					content = DeletedCommentContent
+				}
+				comment.Content = content
+				comment.ContentHTML = sql.NullString{String: RenderCommentMarkdown(content), Valid: true}
+				comment.Author = sql.NullString{}
+				comment.AuthorID = uuid.Nil
+				comment.ModerationReason = sql.NullString{}
+				comment.SpamScore = sql.NullFloat64{}
+			}
+			result.Comments = append(result.Comments, comment)
+			walk(children[comment.ID])
+		}
+	}
+	walk(threads[start:end])
+	return result
+}
+
 func (repo *Repository) GetCommentsByAuthor(ctx context.Context, author_id uuid.UUID, limitNumber *int) ([]Comment, error) {
 	var comments []Comment
 	if limitNumber != nil {
-		err := repo.db.SelectContext(ctx, &comments, "SELECT c.*, u.username FROM comments c JOIN users u ON c.author_id = u.id WHERE author_id = ? LIMIT ?", author_id, limitNumber)
+		err := repo.db.SelectContext(ctx, &comments, commentSelect+" AND c.author_id = ? LIMIT ?", author_id, limitNumber)
 		return comments, err
 	} else {
-		err := repo.db.SelectContext(ctx, &comments, "SELECT c.*, u.username FROM comments c JOIN users u ON c.author_id = u.id WHERE author_id = ?", author_id)
+		err := repo.db.SelectContext(ctx, &comments, commentSelect+" AND c.author_id = ?", author_id)
 		return comments, err
 	}
 }
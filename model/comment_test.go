@@ -0,0 +1,76 @@
+package model
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func testComment(parent *Comment, status string, minute int) Comment {
+	comment := Comment{
+		ID:        uuid.New(),
+		AuthorID:  uuid.New(),
+		Author:    sql.NullString{String: "author", Valid: true},
+		Content:   "content",
+		CreatedAt: time.Date(2024, 1, 1, 0, minute, 0, 0, time.UTC),
+		Status:    status,
+	}
+	if parent != nil {
+		comment.ParentID = uuid.NullUUID{UUID: parent.ID, Valid: true}
+		comment.Depth = parent.Depth + 1
+	}
+	return comment
+}
+
+func TestBuildCommentPageKeepsUnpublishedAncestors(t *testing.T) {
+	pending := testComment(nil, CommentStatusPending, 0)
+	rejected := testComment(&pending, CommentStatusRejected, 1)
+	reply := testComment(&rejected, CommentStatusApproved, 2)
+	trashed := testComment(nil, CommentStatusApproved, 3)
+	trashed.DeletedAt = sql.NullTime{Time: trashed.CreatedAt, Valid: true}
+	trashedReply := testComment(&trashed, CommentStatusApproved, 4)
+	lonely := testComment(nil, CommentStatusPending, 5)
+
+	page := buildCommentPage([]Comment{pending, rejected, reply, trashed, trashedReply, lonely}, CommentSortOldest, 1, 10)
+
+	// 公開済みの返信はすべて表示し、Totalの数と一致する
+	assert.Equal(t, 2, page.Total)
+	assert.Equal(t, 2, page.Threads)
+	ids := make([]uuid.UUID, 0, len(page.Comments))
+	for _, comment := range page.Comments {
+		ids = append(ids, comment.ID)
+	}
+	assert.Equal(t, []uuid.UUID{pending.ID, rejected.ID, reply.ID, trashed.ID, trashedReply.ID}, ids)
+
+	assert.Equal(t, HiddenCommentContent, page.Comments[0].Content)
+	assert.Equal(t, uuid.Nil, page.Comments[0].AuthorID)
+	assert.False(t, page.Comments[0].Author.Valid)
+	assert.Equal(t, 1, page.Comments[0].ReplyCount)
+	assert.Equal(t, HiddenCommentContent, page.Comments[1].Content)
+	assert.Equal(t, "content", page.Comments[2].Content)
+	assert.Equal(t, reply.AuthorID, page.Comments[2].AuthorID)
+	assert.Equal(t, DeletedCommentContent, page.Comments[3].Content)
+}
+
+func TestBuildCommentPagePaging(t *testing.T) {
+	first := testComment(nil, CommentStatusApproved, 0)
+	second := testComment(nil, CommentStatusApproved, 1)
+	reply := testComment(&first, CommentStatusApproved, 2)
+	comments := []Comment{first, second, reply}
+
+	page := buildCommentPage(comments, CommentSortNewest, 1, 1)
+	assert.Equal(t, 3, page.Total)
+	assert.Equal(t, 2, page.Threads)
+	assert.Len(t, page.Comments, 1)
+	assert.Equal(t, second.ID, page.Comments[0].ID)
+
+	page = buildCommentPage(comments, CommentSortNewest, 2, 1)
+	assert.Len(t, page.Comments, 2)
+	assert.Equal(t, first.ID, page.Comments[0].ID)
+	assert.Equal(t, reply.ID, page.Comments[1].ID)
+
+	assert.Empty(t, buildCommentPage(comments, CommentSortNewest, int(^uint(0)>>1), 1).Comments)
+}
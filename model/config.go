@@ -3,6 +3,7 @@ package model
 import (
 	"fmt"
 	"os"
+	"strconv"
 
 	"github.com/go-sql-driver/mysql"
 )
@@ -16,6 +17,19 @@ func getEnv(key, defaultValue string) string {
 	return v
 }
 
+func getEnvInt(key string, defaultValue int) int {
+	v, ok := os.LookupEnv(key)
+	if !ok {
+		return defaultValue
+	}
+	i, err := strconv.Atoi(v)
+	if err != nil {
+		return defaultValue
+	}
+
+	return i
+}
+
 func MySQL() *mysql.Config {
 	c := mysql.NewConfig()
 
@@ -34,3 +48,16 @@ func MySQL() *mysql.Config {
 
 	return c
 }
+
+func getEnvFloat(key string, defaultValue float64) float64 {
+	v, ok := os.LookupEnv(key)
+	if !ok {
+		return defaultValue
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return defaultValue
+	}
+
+	return f
+}
@@ -3,45 +3,84 @@ package model
 import (
 	"bytes"
 	"context"
+	"database/sql"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"os"
+	"time"
+
+	"github.com/google/uuid"
 )
 
+// ContactMessage - お問い合わせの記録。スパムと判定したものはSlackに送らず、管理者が後で確認する
+type ContactMessage struct {
+	ID          uuid.UUID      `db:"id"`
+	Name        string         `db:"name"`
+	Email       string         `db:"email"`
+	Message     string         `db:"message"`
+	IpAddress   sql.NullString `db:"ipaddress"`
+	SpamScore   float64        `db:"spam_score"`
+	SpamReasons sql.NullString `db:"spam_reasons"`
+	IsSpam      bool           `db:"is_spam"`
+	CreatedAt   time.Time      `db:"created_at"`
+}
+
 var slackWebhookForContact string
 
 func InitSlackForContact() {
 	slackWebhookForContact = os.Getenv("SLACK_WEBHOOK_URL_CONTACT")
 }
 
-// postToSlack は Webhook URL が設定されていればメッセージを POST 送信します
-func postToSlack(msg string) (err error) {
-	if slackWebhookForContact == "" {
-		return fmt.Errorf("slackWebhookForContact is not set")
-	}
+// slackClient - SlackのWebhookに送るときのクライアント。応答がなくても送信処理が止まらないようにタイムアウトを付ける
+var slackClient = &http.Client{Timeout: 10 * time.Second}
+
+// postToSlack は Webhook URL にメッセージを POST 送信します
+func postToSlack(ctx context.Context, webhook string, msg string) error {
 	payload := map[string]string{"text": msg}
 	body, _ := json.Marshal(payload)
 
-	req, err := http.NewRequest("POST", slackWebhookForContact, bytes.NewBuffer(body))
+	req, err := http.NewRequestWithContext(ctx, "POST", webhook, bytes.NewBuffer(body))
 	if err != nil {
 		return err
 	}
 	req.Header.Set("Content-Type", "application/json")
 
-	client := &http.Client{}
-	_, err = client.Do(req)
+	resp, err := slackClient.Do(req)
 	if err != nil {
 		return err
 	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("slack webhook returned %s", resp.Status)
+	}
 	return nil
 }
 
 func SendSlack(ctx context.Context, name, email, message string) error {
+	if slackWebhookForContact == "" {
+		return fmt.Errorf("slackWebhookForContact is not set")
+	}
 	msg := "New contact message\n" +
 		"Name: " + name + "\n" +
 		"Email: " + email + "\n" +
 		"Message: " + message
-	err := postToSlack(msg)
+	err := postToSlack(ctx, slackWebhookForContact, msg)
+	return err
+}
+
+func (repo *Repository) CreateContactMessage(ctx context.Context, message ContactMessage) error {
+	_, err := repo.db.NamedExecContext(ctx, "INSERT INTO contact_messages (id, name, email, message, ipaddress, spam_score, spam_reasons, is_spam, created_at) VALUES (:id, :name, :email, :message, :ipaddress, :spam_score, :spam_reasons, :is_spam, :created_at)", message)
 	return err
 }
+
+// GetContactMessages - お問い合わせを新しい順に返す。isSpamを指定した場合はスパムかどうかで絞り込む
+func (repo *Repository) GetContactMessages(ctx context.Context, isSpam *bool) ([]ContactMessage, error) {
+	var messages []ContactMessage
+	if isSpam != nil {
+		err := repo.db.SelectContext(ctx, &messages, "SELECT * FROM contact_messages WHERE is_spam = ? ORDER BY created_at DESC", *isSpam)
+		return messages, err
+	}
+	err := repo.db.SelectContext(ctx, &messages, "SELECT * FROM contact_messages ORDER BY created_at DESC")
+	return messages, err
+}
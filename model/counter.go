@@ -0,0 +1,29 @@
+package model
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// addArticleCounter - 記事のlike_countやcomment_countをdeltaだけ増減する。0未満にはしない
+// columnはSQLに埋め込むので、呼び出し側で固定の列名だけを渡すこと
+func addArticleCounter(ctx context.Context, tx *sqlx.Tx, column string, articleID uuid.UUID, delta int) error {
+	_, err := tx.ExecContext(ctx, "UPDATE articles SET "+column+" = GREATEST("+column+" + ?, 0) WHERE id = ?", delta, articleID)
+	return err
+}
+
+// RepairArticleCounters - いいね数・公開済みのコメント数を実際の件数から数え直す。ずれていた記事の数を返す
+func (repo *Repository) RepairArticleCounters(ctx context.Context) (int64, error) {
+	result, err := repo.db.ExecContext(ctx, `UPDATE articles a
+		LEFT JOIN (SELECT article_id, COUNT(*) AS n FROM likes GROUP BY article_id) l ON l.article_id = a.id
+		LEFT JOIN (SELECT article_id, COUNT(*) AS n FROM comments WHERE deleted_at IS NULL AND status = 'approved' GROUP BY article_id) c ON c.article_id = a.id
+		SET a.like_count = COALESCE(l.n, 0), a.comment_count = COALESCE(c.n, 0)
+		WHERE a.like_count <> COALESCE(l.n, 0) OR a.comment_count <> COALESCE(c.n, 0)`)
+	if err != nil {
+		return 0, err
+	}
+	repo.cache.Clear()
+	return result.RowsAffected()
+}
@@ -31,7 +31,7 @@ func (repo *Repository) CheckIPAddressAndReturnUserID(ctx context.Context, ip st
 	if err != nil {
 		return uuid.Nil, err
 	}
-	return user.ID, nil
+	return newUser.ID, nil
 }
 
 func (repo *Repository) CheckIPAddressAndReturnUserIDWithUserName(ctx context.Context, ip, username string) (uuid.UUID, error) {
@@ -2,6 +2,7 @@ package model
 
 import (
 	"context"
+	"database/sql"
 	"time"
 
 	"github.com/google/uuid"
@@ -31,18 +32,163 @@ func (repo *Repository) GetLikes(ctx context.Context, limitNumber *int) ([]Like,
 	}
 }
 
+// CreateLike - いいねを追加し、同じトランザクションで記事のいいね数を増やす
 func (repo *Repository) CreateLike(ctx context.Context, like Like) error {
-	_, err := repo.db.NamedExecContext(ctx, "INSERT INTO likes (id, article_id, user_id, created_at) VALUES (:id, :article_id, :user_id, :created_at)", like)
+	tx, err := repo.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	_, err = tx.NamedExecContext(ctx, "INSERT INTO likes (id, article_id, user_id, created_at) VALUES (:id, :article_id, :user_id, :created_at)", like)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	err = addArticleCounter(ctx, tx, "like_count", like.ArticleID, 1)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	err = tx.Commit()
+	repo.cache.Delete(cacheKeyArticle(like.ArticleID.String()))
 	return err
 }
 
+// SetLike - 訪問者のいいねをlikedの状態にし、記事の新しいいいね数を返す。すでにその状態なら何もしない
+// 記事の行をロックするため、同じ記事への同時のいいねは順に処理される。記事がない場合はsql.ErrNoRowsを返す
+func (repo *Repository) SetLike(ctx context.Context, articleID uuid.UUID, userID uuid.UUID, liked bool) (int, error) {
+	tx, err := repo.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	var count int
+	err = tx.GetContext(ctx, &count, "SELECT like_count FROM articles WHERE id = ? AND deleted_at IS NULL FOR UPDATE", articleID)
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+	var result sql.Result
+	if liked {
+		result, err = tx.ExecContext(ctx, "INSERT INTO likes (id, article_id, user_id, created_at) VALUES (?, ?, ?, ?) ON DUPLICATE KEY UPDATE id = id", uuid.New(), articleID, userID, time.Now())
+	} else {
+		result, err = tx.ExecContext(ctx, "DELETE FROM likes WHERE article_id = ? AND user_id = ?", articleID, userID)
+	}
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+	if rows > 0 {
+		delta := 1
+		if !liked {
+			delta = -1
+		}
+		err = addArticleCounter(ctx, tx, "like_count", articleID, delta)
+		if err == nil {
+			err = tx.GetContext(ctx, &count, "SELECT like_count FROM articles WHERE id = ?", articleID)
+		}
+		if err != nil {
+			tx.Rollback()
+			return 0, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	if rows > 0 {
+		repo.cache.Delete(cacheKeyArticle(articleID.String()))
+	}
+	return count, nil
+}
+
+// LikeStatus - 記事のいいね数と、訪問者がいいねしているか
+type LikeStatus struct {
+	ArticleID uuid.UUID `db:"id"`
+	LikeCount int       `db:"like_count"`
+	Liked     bool      `db:"liked"`
+}
+
+// GetLikeStatuses - 複数の記事のいいね数と訪問者のいいねを1回のクエリで取得する
+// visitorが無効な場合はLikedはすべてfalse。ゴミ箱にある記事や存在しない記事は含まない
+func (repo *Repository) GetLikeStatuses(ctx context.Context, articleIDs []uuid.UUID, visitor uuid.NullUUID) (map[uuid.UUID]LikeStatus, error) {
+	statuses := make(map[uuid.UUID]LikeStatus)
+	if len(articleIDs) == 0 {
+		return statuses, nil
+	}
+	var rows []LikeStatus
+	err := repo.selectIn(ctx, &rows, "SELECT a.id, a.like_count, l.id IS NOT NULL AS liked FROM articles a LEFT JOIN likes l ON l.article_id = a.id AND l.user_id = ? WHERE a.id IN (?) AND a.deleted_at IS NULL", visitor, articleIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		statuses[row.ArticleID] = row
+	}
+	return statuses, nil
+}
+
+// UpdateLike - 記事が変わった場合は、両方の記事のいいね数を付け替える
 func (repo *Repository) UpdateLike(ctx context.Context, like Like) error {
-	_, err := repo.db.NamedExecContext(ctx, "UPDATE likes SET article_id = :article_id, user_id = :user_id, created_at = :created_at WHERE id = :id", like)
+	tx, err := repo.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	var before Like
+	err = tx.GetContext(ctx, &before, "SELECT * FROM likes WHERE id = ? FOR UPDATE", like.ID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	_, err = tx.NamedExecContext(ctx, "UPDATE likes SET article_id = :article_id, user_id = :user_id, created_at = :created_at WHERE id = :id", like)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if before.ArticleID != like.ArticleID {
+		err = addArticleCounter(ctx, tx, "like_count", before.ArticleID, -1)
+		if err == nil {
+			err = addArticleCounter(ctx, tx, "like_count", like.ArticleID, 1)
+		}
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	err = tx.Commit()
+	repo.cache.Delete(cacheKeyArticle(before.ArticleID.String()), cacheKeyArticle(like.ArticleID.String()))
 	return err
 }
 
+// DeleteLike - いいねを削除し、同じトランザクションで記事のいいね数を減らす
 func (repo *Repository) DeleteLike(ctx context.Context, id uuid.UUID) error {
-	_, err := repo.db.ExecContext(ctx, "DELETE FROM likes WHERE id = ?", id)
+	tx, err := repo.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	var like Like
+	err = tx.GetContext(ctx, &like, "SELECT * FROM likes WHERE id = ? FOR UPDATE", id)
+	if err == sql.ErrNoRows {
+		tx.Rollback()
+		return nil
+	}
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	_, err = tx.ExecContext(ctx, "DELETE FROM likes WHERE id = ?", id)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	err = addArticleCounter(ctx, tx, "like_count", like.ArticleID, -1)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	err = tx.Commit()
+	repo.cache.Delete(cacheKeyArticle(like.ArticleID.String()))
 	return err
 }
 
@@ -70,6 +216,6 @@ func (repo *Repository) GetLikesByUser(ctx context.Context, user_id uuid.UUID, l
 
 func (repo *Repository) GetLikesCountByArticle(ctx context.Context, article_id uuid.UUID) (int, error) {
 	var count int
-	err := repo.db.GetContext(ctx, &count, "SELECT COUNT(*) FROM likes WHERE article_id = ?", article_id)
+	err := repo.db.GetContext(ctx, &count, "SELECT like_count FROM articles WHERE id = ?", article_id)
 	return count, err
 }
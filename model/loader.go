@@ -0,0 +1,150 @@
+package model
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// ArticleRelations - 記事一覧をAPIの形に変換するための関連データ
+// 記事ごとに問い合わせるとN+1になるので、LoadArticleRelationsで一覧の分をまとめて取得する
+// いいね数・コメント数は記事のlike_count・comment_countを使う
+type ArticleRelations struct {
+	AuthorNames  map[uuid.UUID]string
+	Categories   map[uuid.UUID]Category
+	Tags         map[uuid.UUID][]Tag                // 記事IDごと
+	Translations map[uuid.UUID][]ArticleTranslation // 記事IDごと、lang順
+	Series       map[uuid.UUID]*SeriesNavigation    // シリーズに所属する記事のみ
+}
+
+// LoadArticleRelations - 記事の数によらず一定回数のクエリで関連データを取得する
+func (repo *Repository) LoadArticleRelations(ctx context.Context, articles []Article) (*ArticleRelations, error) {
+	relations := &ArticleRelations{
+		AuthorNames:  make(map[uuid.UUID]string),
+		Categories:   make(map[uuid.UUID]Category),
+		Tags:         make(map[uuid.UUID][]Tag),
+		Translations: make(map[uuid.UUID][]ArticleTranslation),
+		Series:       make(map[uuid.UUID]*SeriesNavigation),
+	}
+	if len(articles) == 0 {
+		return relations, nil
+	}
+
+	articleIDs := make([]uuid.UUID, 0, len(articles))
+	authorIDs := make([]uuid.UUID, 0)
+	categoryIDs := make([]uuid.UUID, 0)
+	seenAuthors := make(map[uuid.UUID]bool)
+	seenCategories := make(map[uuid.UUID]bool)
+	for _, article := range articles {
+		articleIDs = append(articleIDs, article.ID)
+		if !seenAuthors[article.AuthorID] {
+			seenAuthors[article.AuthorID] = true
+			authorIDs = append(authorIDs, article.AuthorID)
+		}
+		if !seenCategories[article.CategoryID] {
+			seenCategories[article.CategoryID] = true
+			categoryIDs = append(categoryIDs, article.CategoryID)
+		}
+	}
+
+	var users []User
+	if err := repo.selectIn(ctx, &users, "SELECT * FROM users WHERE id IN (?)", authorIDs); err != nil {
+		return nil, err
+	}
+	for _, user := range users {
+		relations.AuthorNames[user.ID] = user.Username.String
+	}
+	// 1件ずつ取得していたときと同じく、存在しない著者やカテゴリーはエラーにする
+	if len(relations.AuthorNames) != len(authorIDs) {
+		return nil, sql.ErrNoRows
+	}
+
+	var categories []Category
+	if err := repo.selectIn(ctx, &categories, "SELECT * FROM categories WHERE id IN (?)", categoryIDs); err != nil {
+		return nil, err
+	}
+	for _, category := range categories {
+		relations.Categories[category.ID] = category
+	}
+	if len(relations.Categories) != len(categoryIDs) {
+		return nil, sql.ErrNoRows
+	}
+
+	var tags []Tag
+	if err := repo.selectIn(ctx, &tags, "SELECT t.id, at.article_id, t.name FROM tags t JOIN article_tags at ON t.id = at.tag_id WHERE at.article_id IN (?) AND t.deleted_at IS NULL", articleIDs); err != nil {
+		return nil, err
+	}
+	for _, tag := range tags {
+		relations.Tags[tag.ArticleID] = append(relations.Tags[tag.ArticleID], tag)
+	}
+
+	var translations []ArticleTranslation
+	if err := repo.selectIn(ctx, &translations, "SELECT * FROM article_translations WHERE article_id IN (?) ORDER BY article_id, lang", articleIDs); err != nil {
+		return nil, err
+	}
+	for _, t := range translations {
+		relations.Translations[t.ArticleID] = append(relations.Translations[t.ArticleID], t)
+	}
+
+	if err := repo.loadSeriesNavigations(ctx, articleIDs, relations.Series); err != nil {
+		return nil, err
+	}
+	return relations, nil
+}
+
+// loadSeriesNavigations - 記事が所属するシリーズと、そのシリーズの全記事の並びをまとめて取得する
+func (repo *Repository) loadSeriesNavigations(ctx context.Context, articleIDs []uuid.UUID, navigations map[uuid.UUID]*SeriesNavigation) error {
+	var memberships []SeriesArticle
+	if err := repo.selectIn(ctx, &memberships, "SELECT * FROM series_articles WHERE article_id IN (?)", articleIDs); err != nil {
+		return err
+	}
+	if len(memberships) == 0 {
+		return nil
+	}
+	seriesIDs := make([]uuid.UUID, 0)
+	seen := make(map[uuid.UUID]bool)
+	for _, m := range memberships {
+		if !seen[m.SeriesID] {
+			seen[m.SeriesID] = true
+			seriesIDs = append(seriesIDs, m.SeriesID)
+		}
+	}
+
+	var seriesList []Series
+	if err := repo.selectIn(ctx, &seriesList, "SELECT * FROM series WHERE id IN (?)", seriesIDs); err != nil {
+		return err
+	}
+	var parts []SeriesArticle
+	if err := repo.selectIn(ctx, &parts, "SELECT sa.* FROM series_articles sa JOIN articles a ON a.id = sa.article_id WHERE sa.series_id IN (?) AND a.deleted_at IS NULL ORDER BY sa.series_id, sa.position ASC", seriesIDs); err != nil {
+		return err
+	}
+	seriesArticleIDs := make(map[uuid.UUID][]uuid.UUID)
+	for _, part := range parts {
+		seriesArticleIDs[part.SeriesID] = append(seriesArticleIDs[part.SeriesID], part.ArticleID)
+	}
+	seriesByID := make(map[uuid.UUID]Series)
+	for _, series := range seriesList {
+		seriesByID[series.ID] = series
+	}
+	for _, m := range memberships {
+		series, ok := seriesByID[m.SeriesID]
+		if !ok {
+			continue
+		}
+		if nav := buildSeriesNavigation(series, seriesArticleIDs[m.SeriesID], m.ArticleID); nav != nil {
+			navigations[m.ArticleID] = nav
+		}
+	}
+	return nil
+}
+
+// selectIn - "IN (?)"をargsの要素数に展開してSelectContextを実行する
+func (repo *Repository) selectIn(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	query, args, err := sqlx.In(query, args...)
+	if err != nil {
+		return err
+	}
+	return repo.db.SelectContext(ctx, dest, repo.db.Rebind(query), args...)
+}
@@ -0,0 +1,137 @@
+package model
+
+import (
+	"html"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// コメントで使えるMarkdown: コードブロック、インラインコード、リンク、強調
+// 画像と生のHTMLは使えず、そのまま文字として表示する
+var (
+	commentCodeSpan    = regexp.MustCompile("`([^`\n]+)`")
+	commentLink        = regexp.MustCompile(`!?\[([^\]\n]+)\]\(([^()\s]+)\)`)
+	commentAutoLink    = regexp.MustCompile(`https?://[^\s<>()\[\]"]*[^\s<>()\[\]".,;:!?']`)
+	commentStrong      = regexp.MustCompile(`\*\*([^*\n]+?)\*\*`)
+	commentEmphasis    = regexp.MustCompile(`\*([^*\n]+?)\*`)
+	commentFenceLang   = regexp.MustCompile(`^[A-Za-z0-9_+#.-]+$`)
+	allowedLinkSchemes = map[string]bool{"http": true, "https": true, "mailto": true}
+)
+
+// RenderCommentMarkdown - コメントの本文をHTMLにする。入力はすべてエスケープし、作るタグは決まったものだけにする
+func RenderCommentMarkdown(source string) string {
+	lines := strings.Split(strings.ReplaceAll(source, "\r\n", "\n"), "\n")
+	var b strings.Builder
+	var paragraph []string
+	flush := func() {
+		if len(paragraph) == 0 {
+			return
+		}
+		b.WriteString("<p>")
+		for i, line := range paragraph {
+			if i > 0 {
+				b.WriteString("<br>\n")
+			}
+			b.WriteString(renderCommentInline(line))
+		}
+		b.WriteString("</p>\n")
+		paragraph = nil
+	}
+	for i := 0; i < len(lines); i++ {
+		fence, lang, ok := commentFence(lines[i])
+		if !ok {
+			if strings.TrimSpace(lines[i]) == "" {
+				flush()
+			} else {
+				paragraph = append(paragraph, lines[i])
+			}
+			continue
+		}
+		flush()
+		// 閉じていないコードブロックは最後までをコードとして扱う
+		code := make([]string, 0)
+		for i++; i < len(lines) && strings.TrimSpace(lines[i]) != fence; i++ {
+			code = append(code, lines[i])
+		}
+		b.WriteString("<pre><code")
+		if lang != "" {
+			b.WriteString(` class="language-` + html.EscapeString(lang) + `"`)
+		}
+		b.WriteString(">" + html.EscapeString(strings.Join(code, "\n")) + "</code></pre>\n")
+	}
+	flush()
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// commentFence - ```または~~~で始まる行ならフェンスと言語名を返す
+func commentFence(line string) (string, string, bool) {
+	trimmed := strings.TrimSpace(line)
+	for _, fence := range []string{"```", "~~~"} {
+		if strings.HasPrefix(trimmed, fence) {
+			lang := strings.TrimSpace(strings.TrimPrefix(trimmed, fence))
+			if !commentFenceLang.MatchString(lang) {
+				lang = ""
+			}
+			return fence, lang, true
+		}
+	}
+	return "", "", false
+}
+
+// renderCommentInline - 1行をインラインコード・リンク・URL・それ以外の文字に分けてHTMLにする
+func renderCommentInline(text string) string {
+	var b strings.Builder
+	for text != "" {
+		loc, pattern := earliestCommentMatch(text)
+		if loc == nil {
+			b.WriteString(renderCommentEmphasis(text))
+			break
+		}
+		b.WriteString(renderCommentEmphasis(text[:loc[0]]))
+		match := text[loc[0]:loc[1]]
+		switch pattern {
+		case commentCodeSpan:
+			b.WriteString("<code>" + html.EscapeString(match[1:len(match)-1]) + "</code>")
+		case commentLink:
+			sub := commentLink.FindStringSubmatch(match)
+			if strings.HasPrefix(match, "!") || !safeCommentURL(sub[2]) {
+				// 画像や使えないURLのリンクは文字のまま表示する
+				b.WriteString(html.EscapeString(match))
+			} else {
+				b.WriteString(commentAnchor(sub[2], renderCommentEmphasis(sub[1])))
+			}
+		case commentAutoLink:
+			b.WriteString(commentAnchor(match, html.EscapeString(match)))
+		}
+		text = text[loc[1]:]
+	}
+	return b.String()
+}
+
+func earliestCommentMatch(text string) ([]int, *regexp.Regexp) {
+	var first []int
+	var pattern *regexp.Regexp
+	for _, p := range []*regexp.Regexp{commentCodeSpan, commentLink, commentAutoLink} {
+		if loc := p.FindStringIndex(text); loc != nil && (first == nil || loc[0] < first[0]) {
+			first, pattern = loc, p
+		}
+	}
+	return first, pattern
+}
+
+// renderCommentEmphasis - エスケープしてから**強調**と*強調*をタグにする
+func renderCommentEmphasis(text string) string {
+	escaped := html.EscapeString(text)
+	escaped = commentStrong.ReplaceAllString(escaped, "<strong>$1</strong>")
+	return commentEmphasis.ReplaceAllString(escaped, "<em>$1</em>")
+}
+
+func commentAnchor(href string, label string) string {
+	return `<a href="` + html.EscapeString(href) + `" rel="nofollow ugc">` + label + "</a>"
+}
+
+func safeCommentURL(raw string) bool {
+	u, err := url.Parse(raw)
+	return err == nil && allowedLinkSchemes[strings.ToLower(u.Scheme)]
+}
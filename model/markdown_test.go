@@ -0,0 +1,96 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderCommentMarkdown(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   string
+	}{
+		{
+			name:   "plain text",
+			source: "hello",
+			want:   "<p>hello</p>",
+		},
+		{
+			name:   "paragraphs and line breaks",
+			source: "a\nb\n\nc",
+			want:   "<p>a<br>\nb</p>\n<p>c</p>",
+		},
+		{
+			name:   "link",
+			source: "[site](https://example.com)",
+			want:   `<p><a href="https://example.com" rel="nofollow ugc">site</a></p>`,
+		},
+		{
+			name:   "javascript link is left as text",
+			source: "[x](javascript:alert%281%29)",
+			want:   "<p>[x](javascript:alert%281%29)</p>",
+		},
+		{
+			name:   "javascript link with mixed case scheme is left as text",
+			source: "[x](JavaScript:alert%281%29)",
+			want:   "<p>[x](JavaScript:alert%281%29)</p>",
+		},
+		{
+			name:   "quotes cannot break out of href",
+			source: `[x](https://example.com/"onmouseover="alert)`,
+			want:   `<p><a href="https://example.com/&#34;onmouseover=&#34;alert" rel="nofollow ugc">x</a></p>`,
+		},
+		{
+			name:   "image is left as text",
+			source: "![alt](https://example.com/a.png)",
+			want:   "<p>![alt](https://example.com/a.png)</p>",
+		},
+		{
+			name:   "raw html is escaped",
+			source: `<script>alert("x")</script>`,
+			want:   "<p>&lt;script&gt;alert(&#34;x&#34;)&lt;/script&gt;</p>",
+		},
+		{
+			name:   "code fence with language",
+			source: "```go\n<b>x</b>\n```",
+			want:   `<pre><code class="language-go">&lt;b&gt;x&lt;/b&gt;</code></pre>`,
+		},
+		{
+			name:   "code fence with invalid language",
+			source: "```\"><script>\nx\n```",
+			want:   "<pre><code>x</code></pre>",
+		},
+		{
+			name:   "unclosed code fence runs to the end",
+			source: "~~~\n**x**",
+			want:   "<pre><code>**x**</code></pre>",
+		},
+		{
+			name:   "inline code is not formatted",
+			source: "`**x** <b>`",
+			want:   "<p><code>**x** &lt;b&gt;</code></p>",
+		},
+		{
+			name:   "emphasis",
+			source: "**strong** and *em*",
+			want:   "<p><strong>strong</strong> and <em>em</em></p>",
+		},
+		{
+			name:   "emphasis inside link",
+			source: "[**bold** *it*](https://example.com)",
+			want:   `<p><a href="https://example.com" rel="nofollow ugc"><strong>bold</strong> <em>it</em></a></p>`,
+		},
+		{
+			name:   "autolink stops before trailing punctuation",
+			source: "see https://example.com/a?b=1&c=2.",
+			want:   `<p>see <a href="https://example.com/a?b=1&amp;c=2" rel="nofollow ugc">https://example.com/a?b=1&amp;c=2</a>.</p>`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, RenderCommentMarkdown(tt.source))
+		})
+	}
+}
@@ -0,0 +1,198 @@
+package model
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// コメントの公開状態。公開されるのはCommentStatusApprovedのコメントだけ
+const (
+	CommentStatusPending  = "pending"
+	CommentStatusApproved = "approved"
+	CommentStatusRejected = "rejected"
+	CommentStatusSpam     = "spam"
+)
+
+// ValidCommentStatus - statusがコメントの公開状態として正しいかを調べる
+func ValidCommentStatus(status string) bool {
+	switch status {
+	case CommentStatusPending, CommentStatusApproved, CommentStatusRejected, CommentStatusSpam:
+		return true
+	}
+	return false
+}
+
+var linkPattern = regexp.MustCompile(`(?i)https?://|www\.`)
+
+// ModerationRules - 投稿されたコメントを承認待ちにする条件
+type ModerationRules struct {
+	HoldFirstTime bool     // 承認されたコメントがまだない投稿者のコメント
+	HoldLinks     bool     // URLを含むコメント
+	BlockedWords  []string // いずれかを含むコメント (小文字で比較する)
+	Spam          SpamConfig
+}
+
+// ModerationRulesFromEnv - COMMENT_MODERATION_FIRST_TIME・COMMENT_MODERATION_LINKS(既定true)と
+// COMMENT_BLOCKED_WORDS(カンマ区切り)から条件を作る
+func ModerationRulesFromEnv() ModerationRules {
+	return ModerationRules{
+		HoldFirstTime: getEnv("COMMENT_MODERATION_FIRST_TIME", "true") != "false",
+		HoldLinks:     getEnv("COMMENT_MODERATION_LINKS", "true") != "false",
+		BlockedWords:  blockedWordsFromEnv(),
+		Spam:          SpamConfigFromEnv(),
+	}
+}
+
+// blockedWordsFromEnv - COMMENT_BLOCKED_WORDSの禁止語。スパム判定のブロックリストも同じ一覧を使う
+func blockedWordsFromEnv() []string {
+	return splitEnvList("COMMENT_BLOCKED_WORDS")
+}
+
+// Review - コメントを承認待ちにする理由を返す。すぐに公開してよい場合は空文字列を返す
+func (rules ModerationRules) Review(content string, firstTime bool) string {
+	lower := strings.ToLower(content)
+	for _, word := range rules.BlockedWords {
+		if strings.Contains(lower, word) {
+			return "blocked word: " + word
+		}
+	}
+	if rules.HoldLinks && linkPattern.MatchString(content) {
+		return "contains link"
+	}
+	if rules.HoldFirstTime && firstTime {
+		return "first-time commenter"
+	}
+	return ""
+}
+
+// HasApprovedComment - 投稿者に公開済みのコメントがあるかを調べる
+func (repo *Repository) HasApprovedComment(ctx context.Context, authorID uuid.UUID) (bool, error) {
+	var exists bool
+	err := repo.db.GetContext(ctx, &exists, "SELECT EXISTS(SELECT 1 FROM comments WHERE author_id = ? AND status = ? AND deleted_at IS NULL)", authorID, CommentStatusApproved)
+	return exists, err
+}
+
+// ModerateComment - スパムスコアとルールに従ってコメントのStatus・ModerationReason・SpamScoreを決める
+// spamには本文と投稿者名以外の判定材料(ハニーポット、フォームの表示日時など)を渡す
+func (repo *Repository) ModerateComment(ctx context.Context, comment *Comment, rules ModerationRules, spam SpamInput) error {
+	spam.Content = comment.Content
+	spam.Name = comment.Author.String
+	result, err := repo.ScoreSpam(ctx, spam, rules.Spam)
+	if err != nil {
+		return err
+	}
+	comment.SpamScore = sql.NullFloat64{Float64: result.Score, Valid: true}
+	if result.Score >= rules.Spam.SpamThreshold {
+		comment.Status = CommentStatusSpam
+		comment.ModerationReason = sql.NullString{String: result.Reason(), Valid: true}
+		return nil
+	}
+	if result.Score >= rules.Spam.ReviewThreshold {
+		comment.Status = CommentStatusPending
+		comment.ModerationReason = sql.NullString{String: result.Reason(), Valid: true}
+		return nil
+	}
+
+	firstTime := false
+	if rules.HoldFirstTime {
+		approved, err := repo.HasApprovedComment(ctx, comment.AuthorID)
+		if err != nil {
+			return err
+		}
+		firstTime = !approved
+	}
+	reason := rules.Review(comment.Content, firstTime)
+	if reason == "" {
+		comment.Status = CommentStatusApproved
+		comment.ModerationReason = sql.NullString{}
+		return nil
+	}
+	comment.Status = CommentStatusPending
+	comment.ModerationReason = sql.NullString{String: reason, Valid: true}
+	return nil
+}
+
+// GetModerationQueue - 指定した状態のコメントを古い順に返す。ゴミ箱にあるコメントは含めない
+func (repo *Repository) GetModerationQueue(ctx context.Context, status string) ([]Comment, error) {
+	var comments []Comment
+	err := repo.db.SelectContext(ctx, &comments, "SELECT c.*, u.username FROM comments c JOIN users u ON c.author_id = u.id JOIN articles a ON c.article_id = a.id AND a.deleted_at IS NULL WHERE c.deleted_at IS NULL AND c.status = ? ORDER BY c.created_at ASC", status)
+	return comments, err
+}
+
+// SetCommentsStatus - コメントの状態をまとめて変更し、変更した件数を返す
+// 公開済みかどうかが変わったコメントの分だけ、同じトランザクションで記事のコメント数を増減する
+// 承認(ham)とスパムの判定はスパムフィルターの学習にも使う
+func (repo *Repository) SetCommentsStatus(ctx context.Context, ids []uuid.UUID, status string) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	tx, err := repo.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	var comments []Comment
+	query, args, err := sqlx.In("SELECT c.*, u.username FROM comments c JOIN users u ON c.author_id = u.id WHERE c.id IN (?) AND c.deleted_at IS NULL FOR UPDATE", ids)
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+	if err := tx.SelectContext(ctx, &comments, tx.Rebind(query), args...); err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+	var updated int64
+	articleIDs := make([]uuid.UUID, 0)
+	for _, comment := range comments {
+		if comment.Status == status {
+			continue
+		}
+		trainAs := ""
+		switch status {
+		case CommentStatusApproved:
+			trainAs = SpamClassHam
+		case CommentStatusSpam:
+			trainAs = SpamClassSpam
+		}
+		if err := trainSpamModel(ctx, tx, comment.Author.String+"\n"+comment.Content, comment.TrainedAs.String, trainAs); err != nil {
+			tx.Rollback()
+			return 0, err
+		}
+		_, err = tx.ExecContext(ctx, "UPDATE comments SET status = ?, trained_as = ? WHERE id = ?", status, sql.NullString{String: trainAs, Valid: trainAs != ""}, comment.ID)
+		if err != nil {
+			tx.Rollback()
+			return 0, err
+		}
+		delta := 0
+		if status == CommentStatusApproved {
+			delta = 1
+		} else if comment.Status == CommentStatusApproved {
+			delta = -1
+		}
+		if delta != 0 {
+			if err := addArticleCounter(ctx, tx, "comment_count", comment.ArticleID, delta); err != nil {
+				tx.Rollback()
+				return 0, err
+			}
+			articleIDs = append(articleIDs, comment.ArticleID)
+		}
+		if status == CommentStatusApproved {
+			if err := enqueueCommentNotifications(ctx, tx, comment); err != nil {
+				tx.Rollback()
+				return 0, err
+			}
+		}
+		updated++
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	for _, articleID := range articleIDs {
+		repo.cache.Delete(cacheKeyArticle(articleID.String()))
+	}
+	return updated, nil
+}
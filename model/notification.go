@@ -0,0 +1,363 @@
+package model
+
+import (
+	"blog-backend/logger"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// 通知の送信先
+const (
+	NotificationChannelSlack = "slack"
+	NotificationChannelEmail = "email"
+)
+
+// notifications.subjectの長さ(文字数)
+const maxNotificationSubjectLength = 255
+
+// Notification - 送信待ちの通知
+type Notification struct {
+	ID             uuid.UUID      `db:"id"`
+	Channel        string         `db:"channel"`
+	Recipient      sql.NullString `db:"recipient"` // メールアドレス。Slackの場合は無効
+	Subject        string         `db:"subject"`
+	Body           string         `db:"body"`
+	UnsubscribeURL sql.NullString `db:"unsubscribe_url"`
+	Attempts       int            `db:"attempts"`
+	NextAttemptAt  time.Time      `db:"next_attempt_at"`
+	LastError      sql.NullString `db:"last_error"`
+	SentAt         sql.NullTime   `db:"sent_at"`
+	CreatedAt      time.Time      `db:"created_at"`
+}
+
+// CommentSubscription - コメントへの返信をメールで受け取る購読
+type CommentSubscription struct {
+	ID             uuid.UUID    `db:"id"`
+	CommentID      uuid.UUID    `db:"comment_id"`
+	Email          string       `db:"email"`
+	CreatedAt      time.Time    `db:"created_at"`
+	UnsubscribedAt sql.NullTime `db:"unsubscribed_at"`
+}
+
+// NotificationChannels - 記事の著者に新しいコメントを知らせる送信先 (NOTIFY_CHANNELS、カンマ区切り、既定slack)
+func NotificationChannels() []string {
+	return strings.Split(strings.ReplaceAll(getEnv("NOTIFY_CHANNELS", NotificationChannelSlack), " ", ""), ",")
+}
+
+var (
+	unsubscribeSecret     []byte
+	unsubscribeSecretOnce sync.Once
+)
+
+// UnsubscribeToken - 購読ごとの配信停止リンクの署名
+// UNSUBSCRIBE_SECRETがない場合は起動ごとに作るため、再起動すると以前のリンクは使えなくなる
+func UnsubscribeToken(subscriptionID uuid.UUID) string {
+	unsubscribeSecretOnce.Do(func() {
+		if secret := getEnv("UNSUBSCRIBE_SECRET", ""); secret != "" {
+			unsubscribeSecret = []byte(secret)
+			return
+		}
+		logger.Println("UNSUBSCRIBE_SECRET is not set, using a random secret")
+		unsubscribeSecret = make([]byte, 32)
+		if _, err := rand.Read(unsubscribeSecret); err != nil {
+			panic(err)
+		}
+	})
+	mac := hmac.New(sha256.New, unsubscribeSecret)
+	mac.Write([]byte(subscriptionID.String()))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// ValidUnsubscribeToken - 配信停止リンクの署名を確かめる
+func ValidUnsubscribeToken(subscriptionID uuid.UUID, token string) bool {
+	return hmac.Equal([]byte(token), []byte(UnsubscribeToken(subscriptionID)))
+}
+
+func unsubscribeURL(subscriptionID uuid.UUID) string {
+	return strings.TrimRight(getEnv("BASE_URL", "http://localhost:8080"), "/") + "/api/v1/comments/subscriptions/" + subscriptionID.String() + "/unsubscribe?token=" + UnsubscribeToken(subscriptionID)
+}
+
+// SubscribeToReplies - コメントへの返信をメールで受け取るようにする
+func (repo *Repository) SubscribeToReplies(ctx context.Context, commentID uuid.UUID, email string) error {
+	_, err := repo.db.NamedExecContext(ctx, "INSERT INTO comment_subscriptions (id, comment_id, email, created_at) VALUES (:id, :comment_id, :email, :created_at)", CommentSubscription{
+		ID:        uuid.New(),
+		CommentID: commentID,
+		Email:     email,
+		CreatedAt: time.Now(),
+	})
+	return err
+}
+
+// Unsubscribe - 購読をやめる。見つからない場合はsql.ErrNoRowsを返す
+func (repo *Repository) Unsubscribe(ctx context.Context, subscriptionID uuid.UUID) error {
+	var subscription CommentSubscription
+	err := repo.db.GetContext(ctx, &subscription, "SELECT * FROM comment_subscriptions WHERE id = ?", subscriptionID)
+	if err != nil {
+		return err
+	}
+	_, err = repo.db.ExecContext(ctx, "UPDATE comment_subscriptions SET unsubscribed_at = ? WHERE id = ? AND unsubscribed_at IS NULL", time.Now(), subscriptionID)
+	return err
+}
+
+// enqueueCommentNotifications - 公開されたコメントの通知を、コメントを公開するのと同じトランザクションで送信待ちに追加する
+// 記事の著者には設定した送信先に、返信先のコメントを購読している人にはメールで知らせる。一度通知したコメントは通知しない
+func enqueueCommentNotifications(ctx context.Context, tx *sqlx.Tx, comment Comment) error {
+	var notified sql.NullTime
+	if err := tx.GetContext(ctx, &notified, "SELECT notified_at FROM comments WHERE id = ?", comment.ID); err != nil {
+		return err
+	}
+	if notified.Valid {
+		return nil
+	}
+	var article Article
+	if err := tx.GetContext(ctx, &article, "SELECT * FROM articles WHERE id = ?", comment.ArticleID); err != nil {
+		return err
+	}
+	link := ArticlePageLink(article.ID, article.Lang)
+	commenter := comment.Author.String
+	if commenter == "" {
+		commenter = "Anonymous"
+	}
+	notifications := make([]Notification, 0)
+
+	if comment.AuthorID != article.AuthorID {
+		var author User
+		if err := tx.GetContext(ctx, &author, "SELECT * FROM users WHERE id = ?", article.AuthorID); err != nil && err != sql.ErrNoRows {
+			return err
+		}
+		subject := notificationSubject("New comment on \"%s\"", article.Title)
+		body := fmt.Sprintf("%s commented on \"%s\":\n\n%s\n\n%s", commenter, article.Title, comment.Content, link)
+		for _, channel := range NotificationChannels() {
+			switch channel {
+			case NotificationChannelSlack:
+				notifications = append(notifications, newNotification(channel, sql.NullString{}, subject, body, sql.NullString{}))
+			case NotificationChannelEmail:
+				if author.Email.Valid && author.Email.String != "" {
+					notifications = append(notifications, newNotification(channel, author.Email, subject, body, sql.NullString{}))
+				}
+			}
+		}
+	}
+
+	if comment.ParentID.Valid {
+		var subscriptions []CommentSubscription
+		err := tx.SelectContext(ctx, &subscriptions, "SELECT s.* FROM comment_subscriptions s JOIN comments c ON c.id = s.comment_id WHERE s.comment_id = ? AND s.unsubscribed_at IS NULL AND c.author_id <> ?", comment.ParentID.UUID, comment.AuthorID)
+		if err != nil {
+			return err
+		}
+		for _, subscription := range subscriptions {
+			unsubscribe := unsubscribeURL(subscription.ID)
+			subject := notificationSubject("New reply to your comment on \"%s\"", article.Title)
+			body := fmt.Sprintf("%s replied to your comment on \"%s\":\n\n%s\n\n%s\n\nStop receiving replies to this comment: %s", commenter, article.Title, comment.Content, link, unsubscribe)
+			notifications = append(notifications, newNotification(NotificationChannelEmail, sql.NullString{String: subscription.Email, Valid: true}, subject, body, sql.NullString{String: unsubscribe, Valid: true}))
+		}
+	}
+
+	if len(notifications) > 0 {
+		_, err := tx.NamedExecContext(ctx, "INSERT INTO notifications (id, channel, recipient, subject, body, unsubscribe_url, attempts, next_attempt_at, created_at) VALUES (:id, :channel, :recipient, :subject, :body, :unsubscribe_url, :attempts, :next_attempt_at, :created_at)", notifications)
+		if err != nil {
+			return err
+		}
+	}
+	_, err := tx.ExecContext(ctx, "UPDATE comments SET notified_at = ? WHERE id = ?", time.Now(), comment.ID)
+	return err
+}
+
+// notificationSubject - 記事タイトルを埋め込んだ件名を作る。長いタイトルは件名がカラムに収まるよう省略する
+func notificationSubject(format string, title string) string {
+	subject := fmt.Sprintf(format, title)
+	if utf8.RuneCountInString(subject) <= maxNotificationSubjectLength {
+		return subject
+	}
+	overflow := utf8.RuneCountInString(subject) - maxNotificationSubjectLength + 1
+	title = truncateRunes(title, utf8.RuneCountInString(title)-overflow) + "…"
+	return truncateRunes(fmt.Sprintf(format, title), maxNotificationSubjectLength)
+}
+
+// truncateRunes - 文字の途中で切らないよう、先頭からn文字までを返す
+func truncateRunes(s string, n int) string {
+	if n <= 0 {
+		return ""
+	}
+	i := 0
+	for pos := range s {
+		if i == n {
+			return s[:pos]
+		}
+		i++
+	}
+	return s
+}
+
+func newNotification(channel string, recipient sql.NullString, subject string, body string, unsubscribe sql.NullString) Notification {
+	now := time.Now()
+	return Notification{
+		ID:             uuid.New(),
+		Channel:        channel,
+		Recipient:      recipient,
+		Subject:        subject,
+		Body:           body,
+		UnsubscribeURL: unsubscribe,
+		NextAttemptAt:  now,
+		CreatedAt:      now,
+	}
+}
+
+// SendPendingNotifications - 送信時刻になった通知を送る。失敗した通知は待ち時間を倍にしながら最大NOTIFY_MAX_ATTEMPTS回まで再送する
+func (repo *Repository) SendPendingNotifications(ctx context.Context) (int, error) {
+	maxAttempts := getEnvInt("NOTIFY_MAX_ATTEMPTS", 5)
+	var notifications []Notification
+	err := repo.db.SelectContext(ctx, &notifications, "SELECT * FROM notifications WHERE sent_at IS NULL AND attempts < ? AND next_attempt_at <= ? ORDER BY next_attempt_at ASC LIMIT 50", maxAttempts, time.Now())
+	if err != nil {
+		return 0, err
+	}
+	sent := 0
+	for _, notification := range notifications {
+		if err := deliverNotification(ctx, notification); err != nil {
+			attempts := notification.Attempts + 1
+			message := truncateRunes(err.Error(), 500)
+			if attempts >= maxAttempts {
+				logger.Printf("Notification %s failed %d times: %v", notification.ID, attempts, err)
+			}
+			_, err = repo.db.ExecContext(ctx, "UPDATE notifications SET attempts = ?, next_attempt_at = ?, last_error = ? WHERE id = ?",
+				attempts, time.Now().Add(time.Minute<<(attempts-1)), message, notification.ID)
+			if err != nil {
+				return sent, err
+			}
+			continue
+		}
+		_, err = repo.db.ExecContext(ctx, "UPDATE notifications SET attempts = attempts + 1, sent_at = ? WHERE id = ?", time.Now(), notification.ID)
+		if err != nil {
+			return sent, err
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+func deliverNotification(ctx context.Context, notification Notification) error {
+	switch notification.Channel {
+	case NotificationChannelSlack:
+		return sendSlackNotification(ctx, notification)
+	case NotificationChannelEmail:
+		return sendEmailNotification(notification)
+	}
+	return fmt.Errorf("unknown notification channel: %s", notification.Channel)
+}
+
+// sendSlackNotification - NOTIFY_SLACK_WEBHOOK_URLに送る
+func sendSlackNotification(ctx context.Context, notification Notification) error {
+	webhook := getEnv("NOTIFY_SLACK_WEBHOOK_URL", "")
+	if webhook == "" {
+		return fmt.Errorf("NOTIFY_SLACK_WEBHOOK_URL is not set")
+	}
+	return postToSlack(ctx, webhook, notification.Subject+"\n"+notification.Body)
+}
+
+// smtpTimeout - SMTPサーバーへの接続から送信完了までの制限時間
+const smtpTimeout = 30 * time.Second
+
+// sendEmailNotification - SMTP_HOST・SMTP_PORT・SMTP_USER・SMTP_PASS・SMTP_FROMの設定でメールを送る
+func sendEmailNotification(notification Notification) error {
+	host := getEnv("SMTP_HOST", "")
+	from := getEnv("SMTP_FROM", "")
+	if host == "" || from == "" {
+		return fmt.Errorf("SMTP_HOST or SMTP_FROM is not set")
+	}
+	if !notification.Recipient.Valid {
+		return fmt.Errorf("email notification has no recipient")
+	}
+	var auth smtp.Auth
+	if user := getEnv("SMTP_USER", ""); user != "" {
+		auth = smtp.PlainAuth("", user, getEnv("SMTP_PASS", ""), host)
+	}
+	var msg strings.Builder
+	msg.WriteString("From: " + from + "\r\n")
+	msg.WriteString("To: " + notification.Recipient.String + "\r\n")
+	// 件名には記事のタイトルなどASCII以外の文字が入るため、MIMEエンコードする
+	msg.WriteString("Subject: " + mime.QEncoding.Encode("UTF-8", notification.Subject) + "\r\n")
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	if notification.UnsubscribeURL.Valid {
+		// メールソフトの配信停止ボタンから使えるようにする
+		msg.WriteString("List-Unsubscribe: <" + notification.UnsubscribeURL.String + ">\r\n")
+	}
+	msg.WriteString("\r\n")
+	msg.WriteString(strings.ReplaceAll(notification.Body, "\n", "\r\n"))
+	return sendMail(net.JoinHostPort(host, getEnv("SMTP_PORT", "587")), host, auth, from, notification.Recipient.String, []byte(msg.String()))
+}
+
+// sendMail - smtp.SendMailと同じ手順でメールを送る。応答しないサーバーで止まらないよう、全体にsmtpTimeoutを設ける
+func sendMail(addr string, host string, auth smtp.Auth, from string, to string, msg []byte) error {
+	conn, err := net.DialTimeout("tcp", addr, smtpTimeout)
+	if err != nil {
+		return err
+	}
+	if err := conn.SetDeadline(time.Now().Add(smtpTimeout)); err != nil {
+		conn.Close()
+		return err
+	}
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return err
+		}
+	}
+	if auth != nil {
+		if err := client.Auth(auth); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(from); err != nil {
+		return err
+	}
+	if err := client.Rcpt(to); err != nil {
+		return err
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// StartNotificationSender - 送信待ちの通知を定期的に送る
+func StartNotificationSender(repo *Repository, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			if _, err := repo.SendPendingNotifications(context.Background()); err != nil {
+				logger.Println("SendPendingNotifications Error: ", err)
+			}
+			<-ticker.C
+		}
+	}()
+}
@@ -0,0 +1,29 @@
+package model
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotificationSubject(t *testing.T) {
+	assert.Equal(t, `New comment on "hello"`, notificationSubject("New comment on \"%s\"", "hello"))
+
+	// タイトルは255文字まで入るので、件名はカラムより長くなりうる
+	for _, title := range []string{strings.Repeat("a", 255), strings.Repeat("記", 255)} {
+		subject := notificationSubject("New reply to your comment on \"%s\"", title)
+		assert.True(t, utf8.ValidString(subject))
+		assert.Equal(t, maxNotificationSubjectLength, utf8.RuneCountInString(subject))
+		assert.True(t, strings.HasPrefix(subject, "New reply to your comment on \""))
+		assert.True(t, strings.HasSuffix(subject, "…\""))
+	}
+}
+
+func TestTruncateRunes(t *testing.T) {
+	assert.Equal(t, "日本", truncateRunes("日本語", 2))
+	assert.Equal(t, "日本語", truncateRunes("日本語", 3))
+	assert.Equal(t, "日本語", truncateRunes("日本語", 10))
+	assert.Equal(t, "", truncateRunes("日本語", 0))
+}
@@ -0,0 +1,200 @@
+package model
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// リアクションを付けられる対象
+const (
+	ReactionTargetArticle = "article"
+	ReactionTargetComment = "comment"
+)
+
+var (
+	ErrInvalidReactionEmoji   = errors.New("emoji is not an allowed reaction")
+	ErrReactionTargetNotFound = errors.New("reaction target not found")
+)
+
+// reactionTables - 対象ごとのテーブルと対象IDの列
+var reactionTables = map[string]struct {
+	table  string
+	column string
+}{
+	ReactionTargetArticle: {"article_reactions", "article_id"},
+	ReactionTargetComment: {"comment_reactions", "comment_id"},
+}
+
+// ReactionSummary - 対象に付いた絵文字ごとのリアクション数と、訪問者自身が付けたかどうか
+type ReactionSummary struct {
+	Emoji   string
+	Count   int
+	Reacted bool
+}
+
+type reactionCount struct {
+	TargetID uuid.UUID `db:"target_id"`
+	Emoji    string    `db:"emoji"`
+	Count    int       `db:"count"`
+}
+
+// ReactionEmoji - 使える絵文字 (REACTION_EMOJI、カンマ区切り)。この順にリアクション数を返す
+func ReactionEmoji() []string {
+	emoji := make([]string, 0)
+	for _, e := range strings.Split(getEnv("REACTION_EMOJI", "👍,❤️,😂,🎉,😮,😢"), ",") {
+		if e = strings.TrimSpace(e); e != "" {
+			emoji = append(emoji, e)
+		}
+	}
+	return emoji
+}
+
+func ValidReactionTarget(target string) bool {
+	_, ok := reactionTables[target]
+	return ok
+}
+
+func ValidReactionEmoji(emoji string) bool {
+	for _, e := range ReactionEmoji() {
+		if e == emoji {
+			return true
+		}
+	}
+	return false
+}
+
+// AddReaction - リアクションを付ける。すでに付けていた場合は何もせずfalseを返す
+// 同時に押された場合もユニークキーで1件になる
+func (repo *Repository) AddReaction(ctx context.Context, target string, targetID uuid.UUID, userID uuid.UUID, emoji string) (bool, error) {
+	if !ValidReactionEmoji(emoji) {
+		return false, ErrInvalidReactionEmoji
+	}
+	t := reactionTables[target]
+	tx, err := repo.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	if err := checkReactionTarget(ctx, tx, target, targetID); err != nil {
+		tx.Rollback()
+		return false, err
+	}
+	result, err := tx.ExecContext(ctx, "INSERT INTO "+t.table+" (id, "+t.column+", user_id, emoji, created_at) VALUES (?, ?, ?, ?, ?) ON DUPLICATE KEY UPDATE id = id",
+		uuid.New(), targetID, userID, emoji, time.Now())
+	if err != nil {
+		tx.Rollback()
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		tx.Rollback()
+		return false, err
+	}
+	if rows > 0 && target == ReactionTargetComment {
+		_, err = tx.ExecContext(ctx, "UPDATE comments SET reaction_count = reaction_count + 1 WHERE id = ?", targetID)
+		if err != nil {
+			tx.Rollback()
+			return false, err
+		}
+	}
+	return rows > 0, tx.Commit()
+}
+
+// RemoveReaction - リアクションを外す。付けていなかった場合は何もせずfalseを返す
+func (repo *Repository) RemoveReaction(ctx context.Context, target string, targetID uuid.UUID, userID uuid.UUID, emoji string) (bool, error) {
+	t := reactionTables[target]
+	tx, err := repo.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	if err := checkReactionTarget(ctx, tx, target, targetID); err != nil {
+		tx.Rollback()
+		return false, err
+	}
+	result, err := tx.ExecContext(ctx, "DELETE FROM "+t.table+" WHERE "+t.column+" = ? AND user_id = ? AND emoji = ?", targetID, userID, emoji)
+	if err != nil {
+		tx.Rollback()
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		tx.Rollback()
+		return false, err
+	}
+	if rows > 0 && target == ReactionTargetComment {
+		_, err = tx.ExecContext(ctx, "UPDATE comments SET reaction_count = GREATEST(reaction_count - 1, 0) WHERE id = ?", targetID)
+		if err != nil {
+			tx.Rollback()
+			return false, err
+		}
+	}
+	return rows > 0, tx.Commit()
+}
+
+// checkReactionTarget - リアクションの対象が公開されていることを確かめる
+func checkReactionTarget(ctx context.Context, tx *sqlx.Tx, target string, targetID uuid.UUID) error {
+	var exists int
+	var err error
+	switch target {
+	case ReactionTargetArticle:
+		err = tx.GetContext(ctx, &exists, "SELECT 1 FROM articles WHERE id = ? AND deleted_at IS NULL", targetID)
+	case ReactionTargetComment:
+		err = tx.GetContext(ctx, &exists, "SELECT 1 FROM comments WHERE id = ? AND deleted_at IS NULL AND status = ?", targetID, CommentStatusApproved)
+	default:
+		return ErrReactionTargetNotFound
+	}
+	if err == sql.ErrNoRows {
+		return ErrReactionTargetNotFound
+	}
+	return err
+}
+
+// GetReactionSummaries - 対象ごとに、使える絵文字すべてのリアクション数を返す
+// visitorが有効な場合は、その訪問者が付けたリアクションにReactedを立てる
+func (repo *Repository) GetReactionSummaries(ctx context.Context, target string, targetIDs []uuid.UUID, visitor uuid.NullUUID) (map[uuid.UUID][]ReactionSummary, error) {
+	summaries := make(map[uuid.UUID][]ReactionSummary)
+	if len(targetIDs) == 0 {
+		return summaries, nil
+	}
+	t := reactionTables[target]
+	var counts []reactionCount
+	err := repo.selectIn(ctx, &counts, "SELECT "+t.column+" AS target_id, emoji, COUNT(*) AS count FROM "+t.table+" WHERE "+t.column+" IN (?) GROUP BY "+t.column+", emoji", targetIDs)
+	if err != nil {
+		return nil, err
+	}
+	countByTarget := make(map[uuid.UUID]map[string]int)
+	for _, c := range counts {
+		if countByTarget[c.TargetID] == nil {
+			countByTarget[c.TargetID] = make(map[string]int)
+		}
+		countByTarget[c.TargetID][c.Emoji] = c.Count
+	}
+	reacted := make(map[uuid.UUID]map[string]bool)
+	if visitor.Valid {
+		var own []reactionCount
+		err := repo.selectIn(ctx, &own, "SELECT "+t.column+" AS target_id, emoji, 1 AS count FROM "+t.table+" WHERE "+t.column+" IN (?) AND user_id = ?", targetIDs, visitor.UUID)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range own {
+			if reacted[r.TargetID] == nil {
+				reacted[r.TargetID] = make(map[string]bool)
+			}
+			reacted[r.TargetID][r.Emoji] = true
+		}
+	}
+	emoji := ReactionEmoji()
+	for _, id := range targetIDs {
+		list := make([]ReactionSummary, 0, len(emoji))
+		for _, e := range emoji {
+			list = append(list, ReactionSummary{Emoji: e, Count: countByTarget[id][e], Reacted: reacted[id][e]})
+		}
+		summaries[id] = list
+	}
+	return summaries, nil
+}
@@ -21,17 +21,7 @@ func (c *Configuration) RSSmaker(ctx context.Context, articles []Article) error
 	}
 
 	// フィードに記事を追加
-	for _, article := range articles {
-		item := &feeds.Item{
-			Title:       article.Title,
-			Link:        &feeds.Link{Href: getEnv("PAGE_LINK", "http://localhost:5173") + "/article/" + article.ID.String()},
-			Description: article.Content,                                                                   // 必要に応じて要約を使用
-			Author:      &feeds.Author{Name: getEnv("AUTHOR_NAME", ""), Email: getEnv("AUTHOR_EMAIL", "")}, // 著者情報を適宜設定
-			Created:     article.CreatedAt,
-			Id:          article.ID.String(), // GUIDに使用
-		}
-		feed.Items = append(feed.Items, item)
-	}
+	addArticlesToFeed(feed, articles, "")
 
 	// RSS XMLを生成
 	rss, err := feed.ToRss()
@@ -41,22 +31,103 @@ func (c *Configuration) RSSmaker(ctx context.Context, articles []Article) error
 		return err
 	}
 
+	return writeRSSFile("rss.xml", rss)
+}
+
+// LanguageRSSmaker - 対応言語ごとに、その言語で読める最新記事のRSSフィード(rss.<lang>.xml)を生成する
+func (c *Configuration) LanguageRSSmaker(ctx context.Context, repo *Repository) error {
+	for _, lang := range SupportedLanguages() {
+		articles, err := repo.GetArticlesByFilter(ctx, ArticleFilter{Lang: lang, Limit: 5, OrderBy: "created_at", Order: "desc"})
+		if err != nil {
+			logger.Printf("Failed to fetch articles for %s feed: %v", lang, err)
+			return err
+		}
+		for i, article := range articles {
+			translations, err := repo.GetTranslationsByArticle(ctx, article.ID)
+			if err != nil {
+				return err
+			}
+			articles[i], _ = LocalizeArticle(article, translations, lang)
+		}
+
+		feed := &feeds.Feed{
+			Title:       "My Blog",
+			Link:        &feeds.Link{Href: getEnv("PAGE_LINK", "http://localhost:5173") + "?lang=" + lang},
+			Description: "This is my personal blog",
+			Author:      &feeds.Author{Name: getEnv("AUTHOR_NAME", ""), Email: getEnv("AUTHOR_EMAIL", "")},
+			Created:     time.Now(),
+		}
+		addArticlesToFeed(feed, articles, lang)
+
+		rss, err := feed.ToRss()
+		if err != nil {
+			logger.Printf("Failed to generate RSS feed for %s: %v", lang, err)
+			return err
+		}
+		if err := writeRSSFile("rss."+lang+".xml", rss); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeRSSFile(fileName string, rss string) error {
 	// rss.xmlファイルへのパスを指定
-	rssFilePath := filepath.Join("/app/rss", "rss.xml")
+	rssFilePath := filepath.Join("/app/rss", fileName)
 
 	// ディレクトリが存在しない場合は作成
-	err = os.MkdirAll(filepath.Dir(rssFilePath), os.ModePerm)
+	err := os.MkdirAll(filepath.Dir(rssFilePath), os.ModePerm)
 	if err != nil {
-		logger.Printf("Failed to create directory for rss.xml: %v", err)
+		logger.Printf("Failed to create directory for %s: %v", fileName, err)
 		return err
 	}
 
 	// rss.xmlファイルに書き込み
 	err = os.WriteFile(rssFilePath, []byte(rss), 0644)
 	if err != nil {
-		logger.Printf("Failed to write rss.xml: %v", err)
+		logger.Printf("Failed to write %s: %v", fileName, err)
 		return err
 	}
 
 	return nil
 }
+
+// addArticlesToFeed - langが空でない場合は、記事のリンクをその言語のページにする
+func addArticlesToFeed(feed *feeds.Feed, articles []Article, lang string) {
+	for _, article := range articles {
+		link := getEnv("PAGE_LINK", "http://localhost:5173") + "/article/" + article.ID.String()
+		if lang != "" {
+			link = ArticlePageLink(article.ID, lang)
+		}
+		item := &feeds.Item{
+			Title:       article.Title,
+			Link:        &feeds.Link{Href: link},
+			Description: article.Content,                                                                   // 必要に応じて要約を使用
+			Author:      &feeds.Author{Name: getEnv("AUTHOR_NAME", ""), Email: getEnv("AUTHOR_EMAIL", "")}, // 著者情報を適宜設定
+			Created:     article.CreatedAt,
+			Id:          article.ID.String(), // GUIDに使用
+		}
+		feed.Items = append(feed.Items, item)
+	}
+}
+
+// SeriesRSSmaker - シリーズ単位のRSSフィードを生成して返す (ファイルには書き込まない)
+func (c *Configuration) SeriesRSSmaker(ctx context.Context, series Series, articles []Article) (string, error) {
+	feed := &feeds.Feed{
+		Title:       series.Title,
+		Link:        &feeds.Link{Href: getEnv("PAGE_LINK", "http://localhost:5173") + "/series/" + series.ID.String()},
+		Description: series.Description.String,
+		Author:      &feeds.Author{Name: getEnv("AUTHOR_NAME", ""), Email: getEnv("AUTHOR_EMAIL", "")},
+		Created:     series.CreatedAt,
+		Updated:     series.UpdatedAt,
+	}
+
+	addArticlesToFeed(feed, articles, "")
+
+	rss, err := feed.ToRss()
+	if err != nil {
+		logger.Printf("Failed to generate series RSS feed: %v", err)
+		return "", err
+	}
+	return rss, nil
+}
@@ -0,0 +1,162 @@
+package model
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Series struct {
+	ID          uuid.UUID      `db:"id"`
+	Title       string         `db:"title"`
+	Description sql.NullString `db:"description"`
+	CreatedAt   time.Time      `db:"created_at"`
+	UpdatedAt   time.Time      `db:"updated_at"`
+}
+
+type SeriesArticle struct {
+	SeriesID  uuid.UUID `db:"series_id"`
+	ArticleID uuid.UUID `db:"article_id"`
+	Position  int       `db:"position"`
+}
+
+// SeriesNavigation - 記事が所属するシリーズと、その中での前後の記事
+type SeriesNavigation struct {
+	SeriesID      uuid.UUID
+	SeriesTitle   string
+	Part          int // 1始まりの順番
+	Total         int
+	PrevArticleID *uuid.UUID
+	NextArticleID *uuid.UUID
+}
+
+func (repo *Repository) GetSeriesList(ctx context.Context) ([]Series, error) {
+	var series []Series
+	err := repo.db.SelectContext(ctx, &series, "SELECT * FROM series ORDER BY created_at DESC")
+	return series, err
+}
+
+func (repo *Repository) GetSeriesByID(ctx context.Context, id uuid.UUID) (Series, error) {
+	var series Series
+	err := repo.db.GetContext(ctx, &series, "SELECT * FROM series WHERE id = ?", id)
+	return series, err
+}
+
+func (repo *Repository) CreateSeries(ctx context.Context, series Series, articleIDs []uuid.UUID) error {
+	tx, err := repo.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	_, err = tx.NamedExecContext(ctx, "INSERT INTO series (id, title, description, created_at, updated_at) VALUES (:id, :title, :description, :created_at, :updated_at)", series)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	for i, articleID := range articleIDs {
+		_, err = tx.ExecContext(ctx, "INSERT INTO series_articles (series_id, article_id, position) VALUES (?, ?, ?)", series.ID, articleID, i+1)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// UpdateSeries - articleIDsがnilの場合は記事の並びを変更しない
+func (repo *Repository) UpdateSeries(ctx context.Context, series Series, articleIDs []uuid.UUID) error {
+	tx, err := repo.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	_, err = tx.NamedExecContext(ctx, "UPDATE series SET title = :title, description = :description, updated_at = :updated_at WHERE id = :id", series)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if articleIDs != nil {
+		_, err = tx.ExecContext(ctx, "DELETE FROM series_articles WHERE series_id = ?", series.ID)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+		for i, articleID := range articleIDs {
+			_, err = tx.ExecContext(ctx, "INSERT INTO series_articles (series_id, article_id, position) VALUES (?, ?, ?)", series.ID, articleID, i+1)
+			if err != nil {
+				tx.Rollback()
+				return err
+			}
+		}
+	}
+	return tx.Commit()
+}
+
+func (repo *Repository) DeleteSeries(ctx context.Context, id uuid.UUID) error {
+	_, err := repo.db.ExecContext(ctx, "DELETE FROM series WHERE id = ?", id)
+	return err
+}
+
+func (repo *Repository) GetSeriesArticleIDs(ctx context.Context, seriesID uuid.UUID) ([]uuid.UUID, error) {
+	var articleIDs []uuid.UUID
+	err := repo.db.SelectContext(ctx, &articleIDs, "SELECT sa.article_id FROM series_articles sa JOIN articles a ON a.id = sa.article_id WHERE sa.series_id = ? AND a.deleted_at IS NULL ORDER BY sa.position ASC", seriesID)
+	return articleIDs, err
+}
+
+// GetSeriesArticles - シリーズの記事をpositionの順に返す
+func (repo *Repository) GetSeriesArticles(ctx context.Context, seriesID uuid.UUID) ([]Article, error) {
+	var articles []Article
+	err := repo.db.SelectContext(ctx, &articles, "SELECT a.* FROM articles a JOIN series_articles sa ON a.id = sa.article_id WHERE sa.series_id = ? AND a.deleted_at IS NULL ORDER BY sa.position ASC", seriesID)
+	return articles, err
+}
+
+// GetSeriesIDByArticle - 記事がどのシリーズにも所属していない場合はuuid.Nilを返す
+func (repo *Repository) GetSeriesIDByArticle(ctx context.Context, articleID uuid.UUID) (uuid.UUID, error) {
+	var seriesID uuid.UUID
+	err := repo.db.GetContext(ctx, &seriesID, "SELECT series_id FROM series_articles WHERE article_id = ?", articleID)
+	if err == sql.ErrNoRows {
+		return uuid.Nil, nil
+	}
+	return seriesID, err
+}
+
+// GetSeriesNavigationByArticle - 記事がシリーズに所属していない場合はnilを返す
+func (repo *Repository) GetSeriesNavigationByArticle(ctx context.Context, articleID uuid.UUID) (*SeriesNavigation, error) {
+	seriesID, err := repo.GetSeriesIDByArticle(ctx, articleID)
+	if err != nil || seriesID == uuid.Nil {
+		return nil, err
+	}
+	series, err := repo.GetSeriesByID(ctx, seriesID)
+	if err != nil {
+		return nil, err
+	}
+	articleIDs, err := repo.GetSeriesArticleIDs(ctx, seriesID)
+	if err != nil {
+		return nil, err
+	}
+	return buildSeriesNavigation(series, articleIDs, articleID), nil
+}
+
+func buildSeriesNavigation(series Series, articleIDs []uuid.UUID, articleID uuid.UUID) *SeriesNavigation {
+	for i, id := range articleIDs {
+		if id != articleID {
+			continue
+		}
+		nav := &SeriesNavigation{
+			SeriesID:    series.ID,
+			SeriesTitle: series.Title,
+			Part:        i + 1,
+			Total:       len(articleIDs),
+		}
+		if i > 0 {
+			prev := articleIDs[i-1]
+			nav.PrevArticleID = &prev
+		}
+		if i < len(articleIDs)-1 {
+			next := articleIDs[i+1]
+			nav.NextArticleID = &next
+		}
+		return nav
+	}
+	return nil
+}
@@ -16,11 +16,17 @@ import (
 )
 
 type Repository struct {
-	db *sqlx.DB
+	db    *sqlx.DB
+	cache *Cache
 }
 
 func New(db *sqlx.DB) *Repository {
-	return &Repository{db: db}
+	return &Repository{db: db, cache: NewCacheFromEnv()}
+}
+
+// CacheStats - 読み込みキャッシュのヒット・ミス・追い出しの回数
+func (repo *Repository) CacheStats() CacheStats {
+	return repo.cache.Stats()
 }
 
 // Configuration - 画像保存先やベースURLなどの設定を持つ構造体
@@ -62,6 +68,11 @@ func SetupFirstRss(repo *Repository, config *Configuration) error {
 		return err
 	}
 
+	if err := config.LanguageRSSmaker(ctx, repo); err != nil {
+		logger.Printf("Failed to generate language RSS feeds: %v", err)
+		return err
+	}
+
 	return nil
 }
 
@@ -0,0 +1,352 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// 学習に使う分類
+const (
+	SpamClassSpam = "spam"
+	SpamClassHam  = "ham"
+)
+
+const (
+	maxSpamTokens        = 200 // 1件の文書から使うトークンの上限
+	interestingTokens    = 15  // ナイーブベイズで使う、0.5から最も離れたトークンの数
+	untrainedSpamLogOdds = -2.2
+)
+
+var urlPattern = regexp.MustCompile(`(?i)\bhttps?://[^\s<>"]+|\bwww\.[^\s<>"]+`)
+
+// SpamInput - スパム判定に使う投稿の内容
+type SpamInput struct {
+	Content        string
+	Name           string
+	Email          string
+	IPAddress      string
+	Honeypot       string     // 画面に表示しない入力欄の値。人間は入力しないので、値があればボット
+	FormRenderedAt *time.Time // フォームを表示した日時。送信までが短すぎる場合はボット
+	SubmittedAt    time.Time
+}
+
+// SpamResult - スパムスコア (0〜1) と、スコアを上げた理由
+type SpamResult struct {
+	Score   float64
+	Reasons []string
+}
+
+// Reason - 理由をDBに保存できる長さにまとめる
+func (result SpamResult) Reason() string {
+	reason := fmt.Sprintf("spam score %.2f", result.Score)
+	if len(result.Reasons) > 0 {
+		reason += ": " + strings.Join(result.Reasons, ", ")
+	}
+	return truncateRunes(reason, 255)
+}
+
+// SpamConfig - スパム判定の設定
+type SpamConfig struct {
+	SpamThreshold   float64       // これ以上はスパム
+	ReviewThreshold float64       // これ以上は承認待ち
+	MaxLinks        int           // これを超えるURLを含む場合はスパムらしい
+	MinSubmitTime   time.Duration // フォームの表示から送信までの最短時間
+	MinTraining     int           // ナイーブベイズを使うために必要な、分類ごとの学習した文書の数
+	BlockedWords    []string
+	BlockedDomains  []string
+	BlockedIPs      []string
+}
+
+// SpamConfigFromEnv - SPAM_*の環境変数から設定を作る。ブロックリストはカンマ区切り
+// 禁止語はモデレーションのルールと同じCOMMENT_BLOCKED_WORDSを使う
+func SpamConfigFromEnv() SpamConfig {
+	return SpamConfig{
+		SpamThreshold:   getEnvFloat("SPAM_THRESHOLD", 0.9),
+		ReviewThreshold: getEnvFloat("SPAM_REVIEW_THRESHOLD", 0.5),
+		MaxLinks:        getEnvInt("SPAM_MAX_LINKS", 3),
+		MinSubmitTime:   time.Duration(getEnvInt("SPAM_MIN_SUBMIT_SECONDS", 3)) * time.Second,
+		MinTraining:     getEnvInt("SPAM_MIN_TRAINING", 10),
+		BlockedWords:    blockedWordsFromEnv(),
+		BlockedDomains:  splitEnvList("SPAM_BLOCKED_DOMAINS"),
+		BlockedIPs:      splitEnvList("SPAM_BLOCKED_IPS"),
+	}
+}
+
+func splitEnvList(key string) []string {
+	var list []string
+	for _, v := range strings.Split(getEnv(key, ""), ",") {
+		if v = strings.ToLower(strings.TrimSpace(v)); v != "" {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+// ScoreSpam - ハニーポット・ブロックリストに当たった場合は1、それ以外はナイーブベイズの確率にURLの数と送信までの時間を加味したスコアを返す
+// 外部のサービスは使わず、DBにある学習結果だけで判定する
+func (repo *Repository) ScoreSpam(ctx context.Context, input SpamInput, config SpamConfig) (SpamResult, error) {
+	if input.Honeypot != "" {
+		return SpamResult{Score: 1, Reasons: []string{"honeypot filled"}}, nil
+	}
+	if reason := config.blocked(input); reason != "" {
+		return SpamResult{Score: 1, Reasons: []string{reason}}, nil
+	}
+
+	result := SpamResult{}
+	logOdds := untrainedSpamLogOdds
+	probability, trained, err := repo.bayesSpamProbability(ctx, input.Name+"\n"+input.Content, config.MinTraining)
+	if err != nil {
+		return result, err
+	}
+	if trained {
+		logOdds = logit(probability)
+		if probability >= config.ReviewThreshold {
+			result.Reasons = append(result.Reasons, fmt.Sprintf("bayes %.2f", probability))
+		}
+	}
+
+	links := len(urlPattern.FindAllString(input.Content, -1))
+	if links > config.MaxLinks {
+		logOdds += 3
+		result.Reasons = append(result.Reasons, fmt.Sprintf("%d links", links))
+	} else {
+		logOdds += 0.7 * float64(links)
+	}
+
+	// フォームの日時を送らない古いクライアントもあるため、ない場合は判定に使わない
+	if input.FormRenderedAt != nil {
+		elapsed := input.SubmittedAt.Sub(*input.FormRenderedAt)
+		if elapsed < config.MinSubmitTime {
+			logOdds += 3
+			result.Reasons = append(result.Reasons, "submitted too fast")
+		}
+	}
+
+	result.Score = 1 / (1 + math.Exp(-logOdds))
+	return result, nil
+}
+
+// blocked - ブロックリストに当たった理由を返す。当たらない場合は空文字列
+func (config SpamConfig) blocked(input SpamInput) string {
+	if ip := net.ParseIP(input.IPAddress); ip != nil {
+		for _, blocked := range config.BlockedIPs {
+			if _, network, err := net.ParseCIDR(blocked); err == nil && network.Contains(ip) {
+				return "blocked ip"
+			}
+			if blocked == ip.String() {
+				return "blocked ip"
+			}
+		}
+	}
+	text := strings.ToLower(input.Name + "\n" + input.Email + "\n" + input.Content)
+	for _, word := range config.BlockedWords {
+		if strings.Contains(text, word) {
+			return "blocked word: " + word
+		}
+	}
+	for _, host := range linkHosts(input.Content) {
+		for _, domain := range config.BlockedDomains {
+			if host == domain || strings.HasSuffix(host, "."+domain) {
+				return "blocked domain: " + domain
+			}
+		}
+	}
+	if at := strings.LastIndex(input.Email, "@"); at >= 0 {
+		host := strings.ToLower(input.Email[at+1:])
+		for _, domain := range config.BlockedDomains {
+			if host == domain || strings.HasSuffix(host, "."+domain) {
+				return "blocked domain: " + domain
+			}
+		}
+	}
+	return ""
+}
+
+// linkHosts - 本文に含まれるURLのホスト名
+func linkHosts(text string) []string {
+	var hosts []string
+	for _, link := range urlPattern.FindAllString(text, -1) {
+		link = strings.ToLower(link)
+		if i := strings.Index(link, "://"); i >= 0 {
+			link = link[i+3:]
+		}
+		if i := strings.IndexAny(link, "/?#:"); i >= 0 {
+			link = link[:i]
+		}
+		if link != "" {
+			hosts = append(hosts, link)
+		}
+	}
+	return hosts
+}
+
+// spamTokens - 文書をナイーブベイズのトークンに分ける
+// 英数字は単語ごと、日本語のように空白で区切らない文字は2文字ずつ、URLはホスト名をトークンにする
+func spamTokens(text string) []string {
+	seen := make(map[string]bool)
+	tokens := make([]string, 0)
+	add := func(token string) {
+		if len(tokens) >= maxSpamTokens || seen[token] || len(token) > 64 {
+			return
+		}
+		seen[token] = true
+		tokens = append(tokens, token)
+	}
+	for _, host := range linkHosts(text) {
+		add("url:" + host)
+	}
+	text = strings.ToLower(urlPattern.ReplaceAllString(text, " "))
+
+	word := make([]rune, 0)
+	flush := func() {
+		if len(word) == 0 {
+			return
+		}
+		if word[0] < unicode.MaxASCII {
+			if len(word) >= 2 && len(word) <= 32 {
+				add(string(word))
+			}
+		} else if len(word) == 1 {
+			add(string(word))
+		} else {
+			for i := 0; i+1 < len(word); i++ {
+				add(string(word[i : i+2]))
+			}
+		}
+		word = word[:0]
+	}
+	for _, r := range text {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			flush()
+			continue
+		}
+		// 英数字とそれ以外の文字が続いている場合は別の単語にする
+		if len(word) > 0 && (word[0] < unicode.MaxASCII) != (r < unicode.MaxASCII) {
+			flush()
+		}
+		word = append(word, r)
+	}
+	flush()
+	return tokens
+}
+
+type spamToken struct {
+	Token     string `db:"token"`
+	SpamCount int    `db:"spam_count"`
+	HamCount  int    `db:"ham_count"`
+}
+
+type spamDocuments struct {
+	Class     string `db:"class"`
+	Documents int    `db:"documents"`
+}
+
+// bayesSpamProbability - 学習結果からスパムである確率を返す。学習した文書が足りない場合はfalseを返す
+func (repo *Repository) bayesSpamProbability(ctx context.Context, text string, minTraining int) (float64, bool, error) {
+	var documents []spamDocuments
+	if err := repo.db.SelectContext(ctx, &documents, "SELECT * FROM spam_documents"); err != nil {
+		return 0, false, err
+	}
+	var spamDocs, hamDocs int
+	for _, d := range documents {
+		switch d.Class {
+		case SpamClassSpam:
+			spamDocs = d.Documents
+		case SpamClassHam:
+			hamDocs = d.Documents
+		}
+	}
+	if spamDocs < minTraining || hamDocs < minTraining || spamDocs == 0 || hamDocs == 0 {
+		return 0, false, nil
+	}
+
+	tokens := spamTokens(text)
+	if len(tokens) == 0 {
+		return 0.5, true, nil
+	}
+	var counts []spamToken
+	if err := repo.selectIn(ctx, &counts, "SELECT * FROM spam_tokens WHERE token IN (?)", tokens); err != nil {
+		return 0, false, err
+	}
+
+	// Robinsonの方法で、出現回数の少ないトークンの確率を0.5に寄せる
+	probabilities := make([]float64, 0, len(counts))
+	for _, c := range counts {
+		spamRate := float64(c.SpamCount) / float64(spamDocs)
+		hamRate := float64(c.HamCount) / float64(hamDocs)
+		if spamRate+hamRate == 0 {
+			continue
+		}
+		n := float64(c.SpamCount + c.HamCount)
+		p := (0.5 + n*spamRate/(spamRate+hamRate)) / (1 + n)
+		probabilities = append(probabilities, math.Min(math.Max(p, 0.01), 0.99))
+	}
+	sort.Slice(probabilities, func(i, j int) bool {
+		return math.Abs(probabilities[i]-0.5) > math.Abs(probabilities[j]-0.5)
+	})
+	if len(probabilities) > interestingTokens {
+		probabilities = probabilities[:interestingTokens]
+	}
+	var logOdds float64
+	for _, p := range probabilities {
+		logOdds += logit(p)
+	}
+	return 1 / (1 + math.Exp(-logOdds)), true, nil
+}
+
+func logit(p float64) float64 {
+	p = math.Min(math.Max(p, 0.001), 0.999)
+	return math.Log(p / (1 - p))
+}
+
+// trainSpamModel - 文書の学習をfromからtoの分類に付け替える。fromやtoが空の場合は学習の取り消しや追加だけを行う
+func trainSpamModel(ctx context.Context, tx *sqlx.Tx, text string, from string, to string) error {
+	if from == to {
+		return nil
+	}
+	tokens := spamTokens(text)
+	for _, change := range []struct {
+		class string
+		delta int
+	}{{from, -1}, {to, 1}} {
+		if change.class == "" {
+			continue
+		}
+		_, err := tx.ExecContext(ctx, "UPDATE spam_documents SET documents = GREATEST(documents + ?, 0) WHERE class = ?", change.delta, change.class)
+		if err != nil {
+			return err
+		}
+		if len(tokens) == 0 {
+			continue
+		}
+		spamDelta, hamDelta := 0, 0
+		if change.class == SpamClassSpam {
+			spamDelta = change.delta
+		} else {
+			hamDelta = change.delta
+		}
+		values := make([]string, 0, len(tokens))
+		args := make([]interface{}, 0, len(tokens)*3)
+		for _, token := range tokens {
+			values = append(values, "(?, ?, ?)")
+			args = append(args, token, max(spamDelta, 0), max(hamDelta, 0))
+		}
+		// 取り消しの場合は挿入ではなく既存の行の回数を減らす
+		_, err = tx.ExecContext(ctx, "INSERT INTO spam_tokens (token, spam_count, ham_count) VALUES "+strings.Join(values, ", ")+
+			" ON DUPLICATE KEY UPDATE spam_count = GREATEST(spam_count + ?, 0), ham_count = GREATEST(ham_count + ?, 0)",
+			append(args, spamDelta, hamDelta)...)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSpamTokens(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{
+			name: "english words are lowercased and deduplicated",
+			text: "Buy cheap pills, BUY now!",
+			want: []string{"buy", "cheap", "pills", "now"},
+		},
+		{
+			name: "single letters are skipped",
+			text: "a b go",
+			want: []string{"go"},
+		},
+		{
+			name: "japanese is split into bigrams",
+			text: "格安販売",
+			want: []string{"格安", "安販", "販売"},
+		},
+		{
+			name: "a single japanese character is kept",
+			text: "猫",
+			want: []string{"猫"},
+		},
+		{
+			name: "ascii and japanese runs are separate words",
+			text: "iPhone格安",
+			want: []string{"iphone", "格安"},
+		},
+		{
+			name: "urls become host tokens",
+			text: "see https://Spam.example.com/path?q=1 and www.other.example",
+			want: []string{"url:spam.example.com", "url:www.other.example", "see", "and"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, spamTokens(tt.text))
+		})
+	}
+}
+
+func TestSpamTokensLimits(t *testing.T) {
+	// 長すぎる英単語は使わない
+	assert.Empty(t, spamTokens(strings.Repeat("x", 33)))
+
+	words := make([]string, 0, maxSpamTokens+50)
+	for i := 0; i < maxSpamTokens+50; i++ {
+		words = append(words, fmt.Sprintf("w%d", i))
+	}
+	assert.Len(t, spamTokens(strings.Join(words, " ")), maxSpamTokens)
+}
+
+func TestSpamConfigUsesModerationBlockedWords(t *testing.T) {
+	t.Setenv("COMMENT_BLOCKED_WORDS", " Casino ,, pills")
+	config := SpamConfigFromEnv()
+	assert.Equal(t, []string{"casino", "pills"}, config.BlockedWords)
+	assert.Equal(t, ModerationRulesFromEnv().BlockedWords, config.BlockedWords)
+	assert.Equal(t, "blocked word: casino", config.blocked(SpamInput{Content: "Best CASINO online"}))
+	assert.Equal(t, "", config.blocked(SpamInput{Content: "hello"}))
+}
+
+func TestSpamResultReason(t *testing.T) {
+	assert.Equal(t, "spam score 0.50", SpamResult{Score: 0.5}.Reason())
+	assert.Equal(t, "spam score 0.90: too many links, blocked word: casino", SpamResult{Score: 0.9, Reasons: []string{"too many links", "blocked word: casino"}}.Reason())
+
+	// 長い理由は文字の途中で切らずに255文字にする
+	reason := SpamResult{Score: 1, Reasons: []string{"blocked word: " + strings.Repeat("禁止", 200)}}.Reason()
+	assert.True(t, utf8.ValidString(reason))
+	assert.Equal(t, 255, utf8.RuneCountInString(reason))
+}
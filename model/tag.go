@@ -1,11 +1,15 @@
 package model
 
 import (
-	"blog-backend/logger"
 	"context"
+	"database/sql"
 	"errors"
+	"strings"
+	"time"
 
+	"github.com/go-sql-driver/mysql"
 	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
 )
 
 type Tag struct {
@@ -15,42 +19,69 @@ type Tag struct {
 }
 
 type TagItem struct {
-	ID   uuid.UUID `db:"id"`
-	Name string    `db:"name"`
+	ID           uuid.UUID `db:"id"`
+	Name         string    `db:"name"`
+	ArticleCount int       `db:"article_count"` // ゴミ箱にない記事の数。GetTagListのみ
 }
 
+var (
+	ErrTagNameTaken = errors.New("tag name is already in use")
+	ErrTagNotFound  = errors.New("tag not found")
+)
+
 type TagPair struct {
 	TagID     uuid.UUID `db:"tag_id"`
 	ArticleID uuid.UUID `db:"article_id"`
 }
 
 func (r *Repository) GetTagList(ctx context.Context) ([]TagItem, error) {
+	if cached, ok := r.cache.Get(cacheKeyTagList); ok {
+		return append([]TagItem(nil), cached.([]TagItem)...), nil
+	}
 	var tags []TagItem
-	err := r.db.SelectContext(ctx, &tags, "SELECT id, name FROM tags")
+	err := r.db.SelectContext(ctx, &tags, `SELECT t.id, t.name, COUNT(a.id) AS article_count FROM tags t
+		LEFT JOIN article_tags at ON at.tag_id = t.id
+		LEFT JOIN articles a ON a.id = at.article_id AND a.deleted_at IS NULL
+		WHERE t.deleted_at IS NULL GROUP BY t.id, t.name ORDER BY t.name`)
+	if err == nil {
+		r.cache.Set(cacheKeyTagList, append([]TagItem(nil), tags...))
+	}
 	return tags, err
 }
 
 func (r *Repository) AddTagItem(ctx context.Context, tag TagItem) (TagItem, error) {
+	defer r.cache.Delete(cacheKeyTagList)
 	// すでにtagが存在するか確認し、存在しない場合のみ新規作成
+	// ゴミ箱にある同名のtagは復元して使う
 	var t TagItem
 	err := r.db.GetContext(ctx, &t, "SELECT id, name FROM tags WHERE name = ?", tag.Name)
 	if err != nil && err.Error() != "sql: no rows in result set" {
 		return t, err
 	}
 	if err == nil {
-		return t, nil
+		_, err = r.db.ExecContext(ctx, "UPDATE tags SET deleted_at = NULL WHERE id = ?", t.ID)
+		return t, err
 	}
 	_, err = r.db.NamedExecContext(ctx, "INSERT INTO tags (id, name) VALUES (:id, :name)", tag)
 	return tag, err
 }
 
 func (r *Repository) AddTagItemNames(ctx context.Context, tags []TagItem) error {
+	defer r.cache.Delete(cacheKeyTagList)
 	// 存在しないtagのリストを作り、それを新規作成
+	// ゴミ箱にある同名のtagは復元して使う
 	var newTags []TagItem
-	tagsFromDB, err := r.GetTagList(ctx)
+	var tagsFromDB []TagItem
+	err := r.db.SelectContext(ctx, &tagsFromDB, "SELECT id, name FROM tags")
 	if err != nil {
 		return err
 	}
+	for _, tag := range tags {
+		_, err = r.db.ExecContext(ctx, "UPDATE tags SET deleted_at = NULL WHERE name = ?", tag.Name)
+		if err != nil {
+			return err
+		}
+	}
 	for _, tag := range tags {
 		var exists bool
 		for _, t := range tagsFromDB {
@@ -72,11 +103,13 @@ func (r *Repository) AddTagItemNames(ctx context.Context, tags []TagItem) error
 }
 
 func (r *Repository) AddTagPair(ctx context.Context, tagPair TagPair) error {
+	defer r.cache.Delete(cacheKeyTagList)
 	_, err := r.db.NamedExecContext(ctx, "INSERT INTO article_tags (article_id, tag_id) VALUES (:article_id, :tag_id)", tagPair)
 	return err
 }
 
 func (r *Repository) AddTagPairs(ctx context.Context, tagPairs []TagPair) error {
+	defer r.cache.Delete(cacheKeyTagList)
 	_, err := r.db.NamedExecContext(ctx, "INSERT INTO article_tags (article_id, tag_id) VALUES (:article_id, :tag_id)", tagPairs)
 	return err
 }
@@ -92,150 +125,375 @@ func (r *Repository) AddTagPairsByArticle(ctx context.Context, articleID uuid.UU
 func (r *Repository) GetTags(ctx context.Context, limitNumber *int) ([]Tag, error) {
 	var tags []Tag
 	if limitNumber != nil {
-		err := r.db.SelectContext(ctx, &tags, `SELECT t.id, at.article_id, t.name FROM tags t JOIN article_tags at ON t.id = at.tag_id LIMIT ?`, limitNumber)
+		err := r.db.SelectContext(ctx, &tags, `SELECT t.id, at.article_id, t.name FROM tags t JOIN article_tags at ON t.id = at.tag_id WHERE t.deleted_at IS NULL LIMIT ?`, limitNumber)
 		return tags, err
 	} else {
-		err := r.db.SelectContext(ctx, &tags, "SELECT t.id, at.article_id, t.name FROM tags t JOIN article_tags at ON t.id = at.tag_id")
+		err := r.db.SelectContext(ctx, &tags, "SELECT t.id, at.article_id, t.name FROM tags t JOIN article_tags at ON t.id = at.tag_id WHERE t.deleted_at IS NULL")
 		return tags, err
 	}
 }
 
+// AddTag - tag.Nameのタグを記事に付ける。タグがなければ作り、ゴミ箱にあれば復元する
 func (r *Repository) AddTag(ctx context.Context, tag Tag) error {
-	// すでにtagが存在するか確認し、存在しない場合のみ新規作成
-	var t Tag
-	err := r.db.GetContext(ctx, &t, "SELECT t.id, at.article_id, t.name FROM tags t JOIN article_tags at ON t.id = at.tag_id WHERE t.name = ?", tag.Name)
-	if err != nil && err.Error() != "sql: no rows in result set" {
-		return err
-	}
-	if err == nil {
-		return nil
-	}
+	defer r.cache.Delete(cacheKeyTagList)
 	tx, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
 		return err
 	}
-	_, err = tx.NamedExecContext(ctx, "INSERT INTO tags (id, article_id, user_id, name) VALUES (:id, :article_id, :user_id, :name)", tag)
+	tagID, err := tagIDByName(ctx, tx, tag.Name)
 	if err != nil {
 		tx.Rollback()
 		return err
 	}
-	_, err = tx.NamedExecContext(ctx, "INSERT INTO article_tags (article_id, tag_id) VALUES (:article_id, :id)", tag)
+	_, err = tx.ExecContext(ctx, "INSERT INTO article_tags (article_id, tag_id) VALUES (?, ?) ON DUPLICATE KEY UPDATE tag_id = tag_id", tag.ArticleID, tagID)
 	if err != nil {
 		tx.Rollback()
 		return err
 	}
 	err = tx.Commit()
+	r.cache.Delete(cacheKeyArticle(tag.ArticleID.String()))
 	return err
 }
 
-func (r *Repository) AddTags(ctx context.Context, tags []Tag) error {
-	return errors.New("Not implemented")
+// UnknownTagsError - 指定されたタグIDの一部が存在しないかゴミ箱にある
+type UnknownTagsError struct {
+	IDs []uuid.UUID
+}
 
-	// 存在しないtagのリストを作り、それを新規作成
-	var newTags []Tag
-	tagsFromDB, err := r.GetTags(ctx, nil)
+func (e *UnknownTagsError) Error() string {
+	ids := make([]string, 0, len(e.IDs))
+	for _, id := range e.IDs {
+		ids = append(ids, id.String())
+	}
+	return "unknown tag ids: " + strings.Join(ids, ", ")
+}
+
+// SetArticleTags - 記事のタグをtagIDsとnamesのタグに置き換え、置き換えたあとの記事とタグを返す
+// namesのタグはなければ作り、ゴミ箱にあれば復元する。存在しないtagIDsがある場合は何も変えずにUnknownTagsErrorを返す
+// UpdateArticleWithTagsと同様に、article.Versionが現在の版数と異なる場合はErrVersionConflictを返し、成功すると版数が1つ増える
+func (r *Repository) SetArticleTags(ctx context.Context, article Article, tagIDs []uuid.UUID, names []string) (Article, []TagItem, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
-		logger.Println("GetTags error:", err)
-		return err
+		return article, nil, err
 	}
-	for _, tag := range tags {
-		var exists bool
-		for _, t := range tagsFromDB {
-			if tag.Name == t.Name {
-				exists = true
-				break
-			}
+	if err := lockArticleVersion(ctx, tx, article); err != nil {
+		tx.Rollback()
+		return article, nil, err
+	}
+	if err := checkTagIDs(ctx, tx, tagIDs); err != nil {
+		tx.Rollback()
+		return article, nil, err
+	}
+	ids := make([]uuid.UUID, 0, len(tagIDs)+len(names))
+	seen := make(map[uuid.UUID]bool)
+	for _, id := range tagIDs {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
 		}
-		if !exists {
-			newTags = append(newTags, tag)
+	}
+	for _, name := range names {
+		id, err := tagIDByName(ctx, tx, name)
+		if err != nil {
+			tx.Rollback()
+			return article, nil, err
+		}
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
 		}
 	}
-	if len(newTags) == 0 {
-		return nil
+	if err := replaceArticleTags(ctx, tx, article.ID, ids); err != nil {
+		tx.Rollback()
+		return article, nil, err
 	}
+	return r.commitArticleTags(ctx, tx, article, true)
+}
+
+// AddArticleTag - 記事にタグを1つ付け、付けたあとの記事とタグを返す。すでに付いている場合は版数を変えない
+// タグがない場合はUnknownTagsError、article.Versionが現在の版数と異なる場合はErrVersionConflictを返す
+func (r *Repository) AddArticleTag(ctx context.Context, article Article, tagID uuid.UUID) (Article, []TagItem, error) {
 	tx, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
-		logger.Println("BeginTxx error:", err)
-		return err
+		return article, nil, err
+	}
+	if err := lockArticleVersion(ctx, tx, article); err != nil {
+		tx.Rollback()
+		return article, nil, err
 	}
-	_, err = tx.NamedExecContext(ctx, "INSERT INTO tags (id, name) VALUES (:id, :name)", newTags)
+	if err := checkTagIDs(ctx, tx, []uuid.UUID{tagID}); err != nil {
+		tx.Rollback()
+		return article, nil, err
+	}
+	result, err := tx.ExecContext(ctx, "INSERT INTO article_tags (article_id, tag_id) VALUES (?, ?) ON DUPLICATE KEY UPDATE tag_id = tag_id", article.ID, tagID)
 	if err != nil {
 		tx.Rollback()
-		logger.Println("NamedExecContext error:", err)
-		return err
+		return article, nil, err
 	}
-	_, err = tx.NamedExecContext(ctx, "INSERT INTO article_tags (article_id, tag_id) VALUES (:article_id, :id)", newTags)
+	rows, err := result.RowsAffected()
 	if err != nil {
 		tx.Rollback()
-		logger.Println("NamedExecContext error2:", err)
-		return err
+		return article, nil, err
 	}
-	err = tx.Commit()
-	logger.Println("Commit error:", err)
-	return err
+	return r.commitArticleTags(ctx, tx, article, rows > 0)
 }
 
-func (r *Repository) UpdateTag(ctx context.Context, tag Tag) error {
+// RemoveArticleTag - 記事からタグを1つ外し、外したあとの記事とタグを返す。付いていない場合は版数を変えない
+// article.Versionが現在の版数と異なる場合はErrVersionConflictを返す
+func (r *Repository) RemoveArticleTag(ctx context.Context, article Article, tagID uuid.UUID) (Article, []TagItem, error) {
 	tx, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
-		return err
+		return article, nil, err
+	}
+	if err := lockArticleVersion(ctx, tx, article); err != nil {
+		tx.Rollback()
+		return article, nil, err
 	}
-	_, err = tx.NamedExecContext(ctx, "UPDATE tags SET article_id = :article_id, user_id = :user_id, name = :name WHERE id = :id", tag)
+	result, err := tx.ExecContext(ctx, "DELETE FROM article_tags WHERE article_id = ? AND tag_id = ?", article.ID, tagID)
 	if err != nil {
 		tx.Rollback()
-		return err
+		return article, nil, err
 	}
-	_, err = tx.NamedExecContext(ctx, "UPDATE article_tags SET article_id = :article_id WHERE tag_id = :id", tag)
+	rows, err := result.RowsAffected()
 	if err != nil {
 		tx.Rollback()
-		return err
+		return article, nil, err
+	}
+	return r.commitArticleTags(ctx, tx, article, rows > 0)
+}
+
+// lockArticleVersion - 同じ記事を同時に変更しないよう記事の行をロックし、版数がarticle.Versionのままかを確かめる
+// 記事がゴミ箱に移された場合も含め、一致しなければErrVersionConflictを返す
+func lockArticleVersion(ctx context.Context, tx *sqlx.Tx, article Article) error {
+	var version int
+	err := tx.GetContext(ctx, &version, "SELECT version FROM articles WHERE id = ? AND deleted_at IS NULL FOR UPDATE", article.ID)
+	if err == sql.ErrNoRows || (err == nil && version != article.Version) {
+		return ErrVersionConflict
 	}
-	err = tx.Commit()
 	return err
 }
 
-func (r *Repository) DeleteTag(ctx context.Context, id uuid.UUID) error {
-	tx, err := r.db.BeginTxx(ctx, nil)
+// checkTagIDs - すべてのタグがゴミ箱になく存在することを確かめる
+func checkTagIDs(ctx context.Context, tx *sqlx.Tx, tagIDs []uuid.UUID) error {
+	if len(tagIDs) == 0 {
+		return nil
+	}
+	query, args, err := sqlx.In("SELECT id FROM tags WHERE id IN (?) AND deleted_at IS NULL", tagIDs)
 	if err != nil {
 		return err
 	}
-	_, err = tx.ExecContext(ctx, "DELETE FROM tags WHERE id = ?", id)
+	var found []uuid.UUID
+	if err := tx.SelectContext(ctx, &found, tx.Rebind(query), args...); err != nil {
+		return err
+	}
+	exists := make(map[uuid.UUID]bool)
+	for _, id := range found {
+		exists[id] = true
+	}
+	unknown := make([]uuid.UUID, 0)
+	for _, id := range tagIDs {
+		if !exists[id] {
+			exists[id] = true
+			unknown = append(unknown, id)
+		}
+	}
+	if len(unknown) > 0 {
+		return &UnknownTagsError{IDs: unknown}
+	}
+	return nil
+}
+
+// tagIDByName - 名前からタグのIDを返す。なければ作り、ゴミ箱にあれば復元する
+func tagIDByName(ctx context.Context, tx *sqlx.Tx, name string) (uuid.UUID, error) {
+	var id uuid.UUID
+	err := tx.GetContext(ctx, &id, "SELECT id FROM tags WHERE name = ?", name)
+	if err == sql.ErrNoRows {
+		id = uuid.New()
+		_, err = tx.ExecContext(ctx, "INSERT INTO tags (id, name) VALUES (?, ?)", id, name)
+		return id, err
+	}
+	if err != nil {
+		return uuid.Nil, err
+	}
+	_, err = tx.ExecContext(ctx, "UPDATE tags SET deleted_at = NULL WHERE id = ? AND deleted_at IS NOT NULL", id)
+	return id, err
+}
+
+// commitArticleTags - 変更したあとの記事のタグを読んでからコミットする
+// changedの場合はUpdateArticleWithTagsと同様に版数を1つ増やし、article.UpdatedAtを更新日時にする
+func (r *Repository) commitArticleTags(ctx context.Context, tx *sqlx.Tx, article Article, changed bool) (Article, []TagItem, error) {
+	if changed {
+		_, err := tx.ExecContext(ctx, "UPDATE articles SET updated_at = ?, version = version + 1 WHERE id = ?", article.UpdatedAt, article.ID)
+		if err != nil {
+			tx.Rollback()
+			return article, nil, err
+		}
+	}
+	tags := make([]TagItem, 0)
+	err := tx.SelectContext(ctx, &tags, "SELECT t.id, t.name FROM tags t JOIN article_tags at ON t.id = at.tag_id WHERE at.article_id = ? AND t.deleted_at IS NULL ORDER BY t.name", article.ID)
 	if err != nil {
 		tx.Rollback()
+		return article, nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return article, nil, err
+	}
+	if changed {
+		article.Version++
+	}
+	r.cache.Delete(cacheKeyArticle(article.ID.String()), cacheKeyTagList)
+	return article, tags, nil
+}
+
+// replaceArticleTags - 記事のタグをtagIDsで置き換える。呼び出し側でトランザクションをコミットする
+func replaceArticleTags(ctx context.Context, tx *sqlx.Tx, articleID uuid.UUID, tagIDs []uuid.UUID) error {
+	_, err := tx.ExecContext(ctx, "DELETE FROM article_tags WHERE article_id = ?", articleID)
+	if err != nil {
+		return err
+	}
+	for _, tagID := range tagIDs {
+		_, err = tx.ExecContext(ctx, "INSERT INTO article_tags (article_id, tag_id) VALUES (?, ?)", articleID, tagID)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RenameTag - タグの名前を変える。ゴミ箱にあるものも含め、他のタグと同じ名前にはできない
+func (r *Repository) RenameTag(ctx context.Context, id uuid.UUID, name string) error {
+	defer r.cache.Delete(cacheKeyTagList)
+	var otherID uuid.UUID
+	err := r.db.GetContext(ctx, &otherID, "SELECT id FROM tags WHERE name = ? AND id <> ?", name, id)
+	if err == nil {
+		return ErrTagNameTaken
+	}
+	if err != sql.ErrNoRows {
 		return err
 	}
-	_, err = tx.ExecContext(ctx, "DELETE FROM article_tags WHERE tag_id = ?", id)
+	result, err := r.db.ExecContext(ctx, "UPDATE tags SET name = ? WHERE id = ? AND deleted_at IS NULL", name, id)
+	if isDuplicateKeyError(err) {
+		// 確認したあとに同じ名前のタグが作られた
+		return ErrTagNameTaken
+	}
 	if err != nil {
-		tx.Rollback()
 		return err
 	}
-	err = tx.Commit()
-	return err
+	// 名前が同じで変更がない場合も0行になるので、存在するかを確かめ直す
+	if rows, err := result.RowsAffected(); err != nil || rows > 0 {
+		return err
+	}
+	if _, err := r.GetTagItemsByID(ctx, id); err == sql.ErrNoRows {
+		return ErrTagNotFound
+	} else if err != nil {
+		return err
+	}
+	return nil
+}
+
+// MergeTags - sourceIDsのタグをtargetIDのタグにまとめる。同じトランザクションで記事の紐付けを写し、元のタグはゴミ箱に移す
+// 元のタグの紐付けはDeleteTagと同じく残すので、元のタグを復元すると記事も元に戻る
+// すでにtargetIDのタグが付いている記事は重複させない。targetIDのタグが付いた、ゴミ箱にない記事の数を返す
+func (r *Repository) MergeTags(ctx context.Context, sourceIDs []uuid.UUID, targetID uuid.UUID) (int, error) {
+	defer r.cache.Delete(cacheKeyTagList)
+	sources := make([]uuid.UUID, 0, len(sourceIDs))
+	seen := map[uuid.UUID]bool{targetID: true}
+	for _, id := range sourceIDs {
+		if !seen[id] {
+			seen[id] = true
+			sources = append(sources, id)
+		}
+	}
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	var found []uuid.UUID
+	query, args, err := sqlx.In("SELECT id FROM tags WHERE id IN (?) AND deleted_at IS NULL FOR UPDATE", append([]uuid.UUID{targetID}, sources...))
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+	if err := tx.SelectContext(ctx, &found, tx.Rebind(query), args...); err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+	if len(found) != len(sources)+1 {
+		tx.Rollback()
+		return 0, ErrTagNotFound
+	}
+	if len(sources) > 0 {
+		query, args, err = sqlx.In("INSERT INTO article_tags (article_id, tag_id) SELECT DISTINCT article_id, ? FROM article_tags WHERE tag_id IN (?) ON DUPLICATE KEY UPDATE tag_id = VALUES(tag_id)", targetID, sources)
+		if err == nil {
+			_, err = tx.ExecContext(ctx, tx.Rebind(query), args...)
+		}
+		if err != nil {
+			tx.Rollback()
+			return 0, err
+		}
+		query, args, err = sqlx.In("UPDATE tags SET deleted_at = ? WHERE id IN (?)", time.Now(), sources)
+		if err == nil {
+			_, err = tx.ExecContext(ctx, tx.Rebind(query), args...)
+		}
+		if err != nil {
+			tx.Rollback()
+			return 0, err
+		}
+	}
+	var count int
+	err = tx.GetContext(ctx, &count, "SELECT COUNT(*) FROM article_tags at JOIN articles a ON at.article_id = a.id AND a.deleted_at IS NULL WHERE at.tag_id = ?", targetID)
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+	return count, tx.Commit()
+}
+
+// GetArticleIDsByTag - タグが付いている、ゴミ箱にない記事のIDを返す
+func (r *Repository) GetArticleIDsByTag(ctx context.Context, tagID uuid.UUID) ([]uuid.UUID, error) {
+	ids := make([]uuid.UUID, 0)
+	err := r.db.SelectContext(ctx, &ids, "SELECT at.article_id FROM article_tags at JOIN articles a ON at.article_id = a.id WHERE at.tag_id = ? AND a.deleted_at IS NULL", tagID)
+	return ids, err
+}
+
+// isDuplicateKeyError - 一意キーの重複(MySQLのエラー1062)かを調べる
+func isDuplicateKeyError(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == 1062
+}
+
+// DeleteTag - タグをゴミ箱に移す。記事との紐付けは残り、復元すると元に戻る。見つからない場合はfalseを返す
+func (r *Repository) DeleteTag(ctx context.Context, id uuid.UUID) (bool, error) {
+	defer r.cache.Delete(cacheKeyTagList)
+	result, err := r.db.ExecContext(ctx, "UPDATE tags SET deleted_at = ? WHERE id = ? AND deleted_at IS NULL", time.Now(), id)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	return rows > 0, err
 }
 
+// GetTagNameByID - 記事に使われていないタグも取得できるよう、tagsテーブルだけを見る。ArticleIDは設定しない
 func (r *Repository) GetTagNameByID(ctx context.Context, id uuid.UUID) (Tag, error) {
-	return Tag{}, errors.New("Not implemented")
-	// 記事に使われたことがないタグがある場合、そのタグは取得できない
 	var tag Tag
-	err := r.db.GetContext(ctx, &tag, "SELECT t.id, at.article_id, t.name FROM tags t JOIN article_tags at ON t.id = at.tag_id WHERE t.id = ?", id)
+	err := r.db.GetContext(ctx, &tag, "SELECT id, name FROM tags WHERE id = ? AND deleted_at IS NULL", id)
 	return tag, err
 }
 
+// GetTagIDByName - 記事に使われていないタグも取得できるよう、tagsテーブルだけを見る。ArticleIDは設定しない
 func (r *Repository) GetTagIDByName(ctx context.Context, name string) (Tag, error) {
-	return Tag{}, errors.New("Not implemented")
-	// 記事に使われたことがないタグがある場合、そのタグは取得できない
 	var tag Tag
-	err := r.db.GetContext(ctx, &tag, "SELECT t.id, at.article_id, t.name FROM tags t JOIN article_tags at ON t.id = at.tag_id WHERE t.name = ?", name)
+	err := r.db.GetContext(ctx, &tag, "SELECT id, name FROM tags WHERE name = ? AND deleted_at IS NULL", name)
 	return tag, err
 }
 
 func (r *Repository) GetTagsByArticle(ctx context.Context, article_id uuid.UUID, limitNumber *int) ([]Tag, error) {
 	var tags []Tag
 	if limitNumber != nil {
-		err := r.db.SelectContext(ctx, &tags, "SELECT t.id, at.article_id, t.name FROM tags t JOIN article_tags at ON t.id = at.tag_id WHERE at.article_id = ? LIMIT ?", article_id, limitNumber)
+		err := r.db.SelectContext(ctx, &tags, "SELECT t.id, at.article_id, t.name FROM tags t JOIN article_tags at ON t.id = at.tag_id WHERE at.article_id = ? AND t.deleted_at IS NULL LIMIT ?", article_id, limitNumber)
 		return tags, err
 	} else {
-		err := r.db.SelectContext(ctx, &tags, "SELECT t.id, at.article_id, t.name FROM tags t JOIN article_tags at ON t.id = at.tag_id WHERE at.article_id = ?", article_id)
+		err := r.db.SelectContext(ctx, &tags, "SELECT t.id, at.article_id, t.name FROM tags t JOIN article_tags at ON t.id = at.tag_id WHERE at.article_id = ? AND t.deleted_at IS NULL", article_id)
 		return tags, err
 	}
 }
@@ -243,16 +501,16 @@ func (r *Repository) GetTagsByArticle(ctx context.Context, article_id uuid.UUID,
 func (r *Repository) GetTagsByUser(ctx context.Context, user_id uuid.UUID, limitNumber *int) ([]Tag, error) {
 	var tags []Tag
 	if limitNumber != nil {
-		err := r.db.SelectContext(ctx, &tags, "SELECT t.id, at.article_id, t.name FROM tags t JOIN article_tags at ON t.id = at.tag_id WHERE at.user_id = ? LIMIT ?", user_id, limitNumber)
+		err := r.db.SelectContext(ctx, &tags, "SELECT t.id, at.article_id, t.name FROM tags t JOIN article_tags at ON t.id = at.tag_id WHERE at.user_id = ? AND t.deleted_at IS NULL LIMIT ?", user_id, limitNumber)
 		return tags, err
 	} else {
-		err := r.db.SelectContext(ctx, &tags, "SELECT t.id, at.article_id, t.name FROM tags t JOIN article_tags at ON t.id = at.tag_id WHERE at.user_id = ?", user_id)
+		err := r.db.SelectContext(ctx, &tags, "SELECT t.id, at.article_id, t.name FROM tags t JOIN article_tags at ON t.id = at.tag_id WHERE at.user_id = ? AND t.deleted_at IS NULL", user_id)
 		return tags, err
 	}
 }
 
 func (r *Repository) GetTagItemsByID(ctx context.Context, id uuid.UUID) (TagItem, error) {
 	var tag TagItem
-	err := r.db.GetContext(ctx, &tag, "SELECT id, name FROM tags WHERE id = ?", id)
+	err := r.db.GetContext(ctx, &tag, "SELECT id, name FROM tags WHERE id = ? AND deleted_at IS NULL", id)
 	return tag, err
 }
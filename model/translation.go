@@ -0,0 +1,179 @@
+package model
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ArticleTranslation struct {
+	ArticleID uuid.UUID      `db:"article_id"`
+	Lang      string         `db:"lang"`
+	Title     string         `db:"title"`
+	Content   string         `db:"content"`
+	Excerpt   sql.NullString `db:"excerpt"`
+	Slug      string         `db:"slug"`
+	CreatedAt time.Time      `db:"created_at"`
+	UpdatedAt time.Time      `db:"updated_at"`
+}
+
+// DefaultLanguage - 要求された言語の翻訳がない場合に使う言語
+func DefaultLanguage() string {
+	return getEnv("DEFAULT_LANGUAGE", "ja")
+}
+
+// SupportedLanguages - 翻訳を登録できる言語の一覧
+func SupportedLanguages() []string {
+	langs := make([]string, 0)
+	for _, lang := range strings.Split(getEnv("SUPPORTED_LANGUAGES", "ja,en"), ",") {
+		lang = NormalizeLanguage(lang)
+		if lang != "" {
+			langs = append(langs, lang)
+		}
+	}
+	return langs
+}
+
+func IsSupportedLanguage(lang string) bool {
+	for _, l := range SupportedLanguages() {
+		if l == lang {
+			return true
+		}
+	}
+	return false
+}
+
+// NormalizeLanguage - "en-US"や"EN"を"en"のような主言語タグに揃える
+func NormalizeLanguage(lang string) string {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	if i := strings.IndexAny(lang, "-_"); i >= 0 {
+		lang = lang[:i]
+	}
+	return lang
+}
+
+// NegotiateLanguage - langパラメータ、Accept-Language、デフォルト言語の順に、availableの中から言語を選ぶ
+func NegotiateLanguage(available []string, requested string, acceptLanguage string) string {
+	contains := func(lang string) bool {
+		for _, l := range available {
+			if l == lang {
+				return true
+			}
+		}
+		return false
+	}
+	if lang := NormalizeLanguage(requested); lang != "" && contains(lang) {
+		return lang
+	}
+	for _, lang := range parseAcceptLanguage(acceptLanguage) {
+		if contains(lang) {
+			return lang
+		}
+	}
+	if contains(DefaultLanguage()) {
+		return DefaultLanguage()
+	}
+	if len(available) > 0 {
+		return available[0]
+	}
+	return DefaultLanguage()
+}
+
+// parseAcceptLanguage - Accept-Languageヘッダーをq値の降順に並べた言語の一覧にする
+func parseAcceptLanguage(header string) []string {
+	type weighted struct {
+		lang string
+		q    float64
+	}
+	items := make([]weighted, 0)
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(part, ";")
+		lang := NormalizeLanguage(fields[0])
+		if lang == "" || lang == "*" {
+			continue
+		}
+		q := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if v, err := strconv.ParseFloat(strings.TrimPrefix(param, "q="), 64); err == nil {
+					q = v
+				}
+			}
+		}
+		if q <= 0 {
+			continue
+		}
+		items = append(items, weighted{lang: lang, q: q})
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].q > items[j].q
+	})
+	langs := make([]string, 0, len(items))
+	for _, item := range items {
+		langs = append(langs, item.lang)
+	}
+	return langs
+}
+
+// AvailableLanguages - 記事を読める言語の一覧 (元記事の言語が先頭)
+func AvailableLanguages(article Article, translations []ArticleTranslation) []string {
+	langs := []string{article.Lang}
+	for _, t := range translations {
+		if t.Lang != article.Lang {
+			langs = append(langs, t.Lang)
+		}
+	}
+	return langs
+}
+
+// LocalizeArticle - 指定された言語の翻訳でタイトルと本文を置き換える。該当する翻訳がなければそのまま返す
+func LocalizeArticle(article Article, translations []ArticleTranslation, lang string) (Article, *ArticleTranslation) {
+	for i, t := range translations {
+		if t.Lang == lang {
+			article.Title = t.Title
+			article.Content = t.Content
+			article.Lang = t.Lang
+			return article, &translations[i]
+		}
+	}
+	return article, nil
+}
+
+// ArticlePageLink - 言語ごとの記事ページのURL (hreflangの代替リンクに使う)
+func ArticlePageLink(articleID uuid.UUID, lang string) string {
+	return getEnv("PAGE_LINK", "http://localhost:5173") + "/article/" + articleID.String() + "?lang=" + lang
+}
+
+func (repo *Repository) GetTranslationsByArticle(ctx context.Context, articleID uuid.UUID) ([]ArticleTranslation, error) {
+	var translations []ArticleTranslation
+	err := repo.db.SelectContext(ctx, &translations, "SELECT * FROM article_translations WHERE article_id = ? ORDER BY lang", articleID)
+	return translations, err
+}
+
+func (repo *Repository) GetTranslation(ctx context.Context, articleID uuid.UUID, lang string) (ArticleTranslation, error) {
+	var translation ArticleTranslation
+	err := repo.db.GetContext(ctx, &translation, "SELECT * FROM article_translations WHERE article_id = ? AND lang = ?", articleID, lang)
+	return translation, err
+}
+
+func (repo *Repository) GetTranslationBySlug(ctx context.Context, lang string, slug string) (ArticleTranslation, error) {
+	var translation ArticleTranslation
+	err := repo.db.GetContext(ctx, &translation, "SELECT * FROM article_translations WHERE lang = ? AND slug = ?", lang, slug)
+	return translation, err
+}
+
+func (repo *Repository) UpsertTranslation(ctx context.Context, translation ArticleTranslation) error {
+	_, err := repo.db.NamedExecContext(ctx, "INSERT INTO article_translations (article_id, lang, title, content, excerpt, slug, created_at, updated_at) VALUES (:article_id, :lang, :title, :content, :excerpt, :slug, :created_at, :updated_at) ON DUPLICATE KEY UPDATE title = VALUES(title), content = VALUES(content), excerpt = VALUES(excerpt), slug = VALUES(slug), updated_at = VALUES(updated_at)", translation)
+	return err
+}
+
+func (repo *Repository) DeleteTranslation(ctx context.Context, articleID uuid.UUID, lang string) error {
+	_, err := repo.db.ExecContext(ctx, "DELETE FROM article_translations WHERE article_id = ? AND lang = ?", articleID, lang)
+	return err
+}
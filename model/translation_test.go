@@ -0,0 +1,100 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNegotiateLanguage(t *testing.T) {
+	t.Setenv("DEFAULT_LANGUAGE", "ja")
+	tests := []struct {
+		name           string
+		available      []string
+		requested      string
+		acceptLanguage string
+		want           string
+	}{
+		{
+			name:           "lang parameter wins over Accept-Language",
+			available:      []string{"ja", "en"},
+			requested:      "en",
+			acceptLanguage: "ja",
+			want:           "en",
+		},
+		{
+			name:           "lang parameter is normalized",
+			available:      []string{"ja", "en"},
+			requested:      "EN-us",
+			acceptLanguage: "",
+			want:           "en",
+		},
+		{
+			name:           "unavailable lang parameter falls back to Accept-Language",
+			available:      []string{"ja", "en"},
+			requested:      "fr",
+			acceptLanguage: "en",
+			want:           "en",
+		},
+		{
+			name:           "highest q value first",
+			available:      []string{"ja", "en"},
+			acceptLanguage: "ja;q=0.5, en-US;q=0.9",
+			want:           "en",
+		},
+		{
+			name:           "order is kept for equal q values",
+			available:      []string{"ja", "en"},
+			acceptLanguage: "en, ja",
+			want:           "en",
+		},
+		{
+			name:           "q=0 is excluded",
+			available:      []string{"ja", "en"},
+			acceptLanguage: "en;q=0, fr",
+			want:           "ja",
+		},
+		{
+			name:           "wildcard is ignored",
+			available:      []string{"ja", "en"},
+			acceptLanguage: "*",
+			want:           "ja",
+		},
+		{
+			name:           "malformed q value counts as 1",
+			available:      []string{"ja", "en"},
+			acceptLanguage: "ja;q=0.8, en;q=abc",
+			want:           "en",
+		},
+		{
+			name:           "default language when nothing matches",
+			available:      []string{"en", "ja"},
+			acceptLanguage: "de, fr",
+			want:           "ja",
+		},
+		{
+			name:           "first available language when the default is unavailable",
+			available:      []string{"en", "fr"},
+			acceptLanguage: "de",
+			want:           "en",
+		},
+		{
+			name:           "default language when nothing is available",
+			available:      nil,
+			acceptLanguage: "en",
+			want:           "ja",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, NegotiateLanguage(tt.available, tt.requested, tt.acceptLanguage))
+		})
+	}
+}
+
+func TestNormalizeLanguage(t *testing.T) {
+	assert.Equal(t, "en", NormalizeLanguage(" en-US "))
+	assert.Equal(t, "zh", NormalizeLanguage("zh_Hant"))
+	assert.Equal(t, "ja", NormalizeLanguage("JA"))
+	assert.Equal(t, "", NormalizeLanguage(""))
+}
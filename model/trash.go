@@ -0,0 +1,120 @@
+package model
+
+import (
+	"blog-backend/logger"
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TrashItem - ゴミ箱にある記事・コメント・タグ
+type TrashItem struct {
+	ID        uuid.UUID `db:"id"`
+	Label     string    `db:"label"` // 記事のタイトル、コメント本文、タグ名
+	DeletedAt time.Time `db:"deleted_at"`
+}
+
+// TrashRetention - ゴミ箱に入れてから完全に削除するまでの期間
+func TrashRetention() time.Duration {
+	return time.Duration(getEnvInt("TRASH_RETENTION_DAYS", 30)) * 24 * time.Hour
+}
+
+func (repo *Repository) GetTrashedArticles(ctx context.Context) ([]TrashItem, error) {
+	var items []TrashItem
+	err := repo.db.SelectContext(ctx, &items, "SELECT id, title AS label, deleted_at FROM articles WHERE deleted_at IS NOT NULL ORDER BY deleted_at DESC")
+	return items, err
+}
+
+func (repo *Repository) GetTrashedComments(ctx context.Context) ([]TrashItem, error) {
+	var items []TrashItem
+	err := repo.db.SelectContext(ctx, &items, "SELECT id, content AS label, deleted_at FROM comments WHERE deleted_at IS NOT NULL ORDER BY deleted_at DESC")
+	return items, err
+}
+
+func (repo *Repository) GetTrashedTags(ctx context.Context) ([]TrashItem, error) {
+	var items []TrashItem
+	err := repo.db.SelectContext(ctx, &items, "SELECT id, name AS label, deleted_at FROM tags WHERE deleted_at IS NOT NULL ORDER BY deleted_at DESC")
+	return items, err
+}
+
+// RestoreArticle - ゴミ箱から記事を戻す。ゴミ箱になかった場合はfalseを返す
+func (repo *Repository) RestoreArticle(ctx context.Context, id uuid.UUID) (bool, error) {
+	defer repo.cache.Delete(cacheKeyArticle(id.String()), cacheKeyTagList)
+	return repo.restore(ctx, "UPDATE articles SET deleted_at = NULL WHERE id = ? AND deleted_at IS NOT NULL", id)
+}
+
+// RestoreComment - ゴミ箱からコメントを戻す。ゴミ箱になかった場合はfalseを返す
+func (repo *Repository) RestoreComment(ctx context.Context, id uuid.UUID) (bool, error) {
+	return repo.setCommentDeletedAt(ctx, id, sql.NullTime{})
+}
+
+// RestoreTag - ゴミ箱からタグを戻す。ゴミ箱になかった場合はfalseを返す
+func (repo *Repository) RestoreTag(ctx context.Context, id uuid.UUID) (bool, error) {
+	defer repo.cache.Delete(cacheKeyTagList)
+	return repo.restore(ctx, "UPDATE tags SET deleted_at = NULL WHERE id = ? AND deleted_at IS NOT NULL", id)
+}
+
+func (repo *Repository) restore(ctx context.Context, query string, id uuid.UUID) (bool, error) {
+	result, err := repo.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	return rows > 0, err
+}
+
+// PurgeTrash - before以前にゴミ箱に入れられた項目を完全に削除する
+// コメント・いいね・タグの紐付けは外部キーのON DELETE CASCADEで一緒に削除される
+// 返信が残っているコメントはそのまま残す
+func (repo *Repository) PurgeTrash(ctx context.Context, before time.Time) (int64, error) {
+	tx, err := repo.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	var purged int64
+	for _, query := range []string{
+		// 返信が残っているコメントは"[deleted]"として表示するため削除しない。返信が削除されたあとの実行で削除される
+		"DELETE FROM comments WHERE deleted_at IS NOT NULL AND deleted_at < ? AND id NOT IN (SELECT parent_id FROM (SELECT DISTINCT parent_id FROM comments WHERE parent_id IS NOT NULL) AS parents)",
+		"DELETE FROM articles WHERE deleted_at IS NOT NULL AND deleted_at < ?",
+		"DELETE FROM tags WHERE deleted_at IS NOT NULL AND deleted_at < ?",
+	} {
+		result, err := tx.ExecContext(ctx, query, before)
+		if err != nil {
+			tx.Rollback()
+			return 0, err
+		}
+		rows, err := result.RowsAffected()
+		if err != nil {
+			tx.Rollback()
+			return 0, err
+		}
+		purged += rows
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	if purged > 0 {
+		// 記事やタグ一覧(タグごとの記事数)を含め、すべて読み直させる
+		repo.cache.Clear()
+	}
+	return purged, nil
+}
+
+// StartTrashPurger - 保持期間を過ぎたゴミ箱の項目を定期的に完全に削除する
+func StartTrashPurger(repo *Repository, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			purged, err := repo.PurgeTrash(context.Background(), time.Now().Add(-TrashRetention()))
+			if err != nil {
+				logger.Println("PurgeTrash Error: ", err)
+			} else if purged > 0 {
+				logger.Printf("Purged %d items from trash", purged)
+			}
+			<-ticker.C
+		}
+	}()
+}
@@ -0,0 +1,143 @@
+package model
+
+import (
+	"blog-backend/logger"
+	"context"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// トレンドの集計期間
+const (
+	TrendingPeriodDay   = "24h"
+	TrendingPeriodWeek  = "7d"
+	TrendingPeriodMonth = "30d"
+)
+
+var trendingPeriods = map[string]time.Duration{
+	TrendingPeriodDay:   24 * time.Hour,
+	TrendingPeriodWeek:  7 * 24 * time.Hour,
+	TrendingPeriodMonth: 30 * 24 * time.Hour,
+}
+
+// TrendingScore - 集計期間ごとの記事のスコア
+type TrendingScore struct {
+	ArticleID  uuid.UUID `db:"article_id"`
+	CategoryID uuid.UUID `db:"category_id"`
+	Score      float64   `db:"score"`
+	ComputedAt time.Time `db:"computed_at"`
+}
+
+// TrendingWeights - 閲覧・いいね・コメント1件あたりの重み
+// (TRENDING_VIEW_WEIGHT・TRENDING_LIKE_WEIGHT・TRENDING_COMMENT_WEIGHT)
+type TrendingWeights struct {
+	View    float64
+	Like    float64
+	Comment float64
+}
+
+func TrendingWeightsFromEnv() TrendingWeights {
+	return TrendingWeights{
+		View:    getEnvFloat("TRENDING_VIEW_WEIGHT", 1),
+		Like:    getEnvFloat("TRENDING_LIKE_WEIGHT", 5),
+		Comment: getEnvFloat("TRENDING_COMMENT_WEIGHT", 10),
+	}
+}
+
+// TrendingRefreshInterval - トレンドのスコアを計算し直す間隔 (TRENDING_REFRESH_MINUTES、既定15分)
+func TrendingRefreshInterval() time.Duration {
+	return time.Duration(max(getEnvInt("TRENDING_REFRESH_MINUTES", 15), 1)) * time.Minute
+}
+
+func ValidTrendingPeriod(period string) bool {
+	_, ok := trendingPeriods[period]
+	return ok
+}
+
+// TrendingHalfLife - スコアが半分になるまでの時間。集計期間にTRENDING_HALF_LIFE_RATIO (既定0.25) を掛けたもの
+func TrendingHalfLife(period string) time.Duration {
+	return time.Duration(float64(trendingPeriods[period]) * getEnvFloat("TRENDING_HALF_LIFE_RATIO", 0.25))
+}
+
+// trendingDecayRate - 1秒あたりの減衰率。経過秒数に掛けてEXP(-x)にすると、半減期でちょうど0.5になる
+func trendingDecayRate(period string) float64 {
+	return math.Ln2 / TrendingHalfLife(period).Seconds()
+}
+
+// RefreshTrending - すべての集計期間のスコアを計算し直す
+// 期間内の閲覧・いいね・公開済みのコメントそれぞれに重みを掛け、経過時間に応じて指数関数的に減衰させて合計する
+func (repo *Repository) RefreshTrending(ctx context.Context, now time.Time) error {
+	weights := TrendingWeightsFromEnv()
+	tx, err := repo.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	for period, length := range trendingPeriods {
+		since := now.Add(-length)
+		decay := trendingDecayRate(period)
+		_, err = tx.ExecContext(ctx, "DELETE FROM article_trending WHERE period = ?", period)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+		_, err = tx.ExecContext(ctx, `INSERT INTO article_trending (article_id, period, score, computed_at)
+			SELECT e.article_id, ?, SUM(e.weight * EXP(-? * GREATEST(TIMESTAMPDIFF(SECOND, e.at, ?), 0))), ?
+			FROM (
+				SELECT article_id, hour AS at, views * ? AS weight FROM article_view_hours WHERE hour >= ?
+				UNION ALL
+				SELECT article_id, created_at, ? FROM likes WHERE created_at >= ?
+				UNION ALL
+				SELECT article_id, created_at, ? FROM comments WHERE created_at >= ? AND deleted_at IS NULL AND status = ?
+			) e
+			JOIN articles a ON a.id = e.article_id AND a.deleted_at IS NULL
+			GROUP BY e.article_id`,
+			period, decay, now, now,
+			weights.View, since.Truncate(time.Hour),
+			weights.Like, since,
+			weights.Comment, since, CommentStatusApproved)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	// 最も長い集計期間より古い閲覧数はもう使わない
+	_, err = tx.ExecContext(ctx, "DELETE FROM article_view_hours WHERE hour < ?", now.Add(-trendingPeriods[TrendingPeriodMonth]).Truncate(time.Hour))
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetTrendingScores - 集計期間のスコアをスコアの高い順に返す。ゴミ箱にある記事は含めない
+func (repo *Repository) GetTrendingScores(ctx context.Context, period string) ([]TrendingScore, error) {
+	var scores []TrendingScore
+	err := repo.db.SelectContext(ctx, &scores, "SELECT t.article_id, a.category_id, t.score, t.computed_at FROM article_trending t JOIN articles a ON a.id = t.article_id WHERE t.period = ? AND a.deleted_at IS NULL ORDER BY t.score DESC", period)
+	return scores, err
+}
+
+// GetArticlesByIDs - 記事をまとめて取得する。ゴミ箱にある記事は含めない
+func (repo *Repository) GetArticlesByIDs(ctx context.Context, ids []uuid.UUID) ([]Article, error) {
+	articles := make([]Article, 0)
+	if len(ids) == 0 {
+		return articles, nil
+	}
+	err := repo.selectIn(ctx, &articles, "SELECT * FROM articles WHERE id IN (?) AND deleted_at IS NULL", ids)
+	return articles, err
+}
+
+// StartTrendingRefresher - トレンドのスコアを定期的に計算し直す
+func StartTrendingRefresher(repo *Repository, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			if err := repo.RefreshTrending(context.Background(), time.Now()); err != nil {
+				logger.Println("RefreshTrending Error: ", err)
+			}
+			<-ticker.C
+		}
+	}()
+}
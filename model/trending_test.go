@@ -0,0 +1,51 @@
+package model
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrendingHalfLife(t *testing.T) {
+	assert.Equal(t, 6*time.Hour, TrendingHalfLife(TrendingPeriodDay))
+	assert.Equal(t, 42*time.Hour, TrendingHalfLife(TrendingPeriodWeek))
+	assert.Equal(t, 180*time.Hour, TrendingHalfLife(TrendingPeriodMonth))
+
+	t.Setenv("TRENDING_HALF_LIFE_RATIO", "0.5")
+	assert.Equal(t, 12*time.Hour, TrendingHalfLife(TrendingPeriodDay))
+	assert.Equal(t, 84*time.Hour, TrendingHalfLife(TrendingPeriodWeek))
+	assert.Equal(t, 360*time.Hour, TrendingHalfLife(TrendingPeriodMonth))
+
+	// 数値でない値は既定の0.25を使う
+	t.Setenv("TRENDING_HALF_LIFE_RATIO", "abc")
+	assert.Equal(t, 6*time.Hour, TrendingHalfLife(TrendingPeriodDay))
+}
+
+func TestTrendingDecayRate(t *testing.T) {
+	for period := range trendingPeriods {
+		rate := trendingDecayRate(period)
+		halfLife := TrendingHalfLife(period).Seconds()
+		// RefreshTrendingのEXP(-rate * 経過秒数)と同じ計算
+		assert.InDelta(t, 0.5, math.Exp(-rate*halfLife), 1e-9, period)
+		assert.InDelta(t, 0.25, math.Exp(-rate*2*halfLife), 1e-9, period)
+	}
+	// 期間が短いほど速く減衰する
+	assert.Greater(t, trendingDecayRate(TrendingPeriodDay), trendingDecayRate(TrendingPeriodWeek))
+	assert.Greater(t, trendingDecayRate(TrendingPeriodWeek), trendingDecayRate(TrendingPeriodMonth))
+
+	// 半減期を変えると減衰率も変わる。24時間の期間で半減期12時間なら、12時間前の閲覧は半分の重みになる
+	t.Setenv("TRENDING_HALF_LIFE_RATIO", "0.5")
+	rate := trendingDecayRate(TrendingPeriodDay)
+	assert.InDelta(t, 0.5, math.Exp(-rate*(12*time.Hour).Seconds()), 1e-9)
+	assert.InDelta(t, 0.25, math.Exp(-rate*(24*time.Hour).Seconds()), 1e-9)
+}
+
+func TestValidTrendingPeriod(t *testing.T) {
+	assert.True(t, ValidTrendingPeriod("24h"))
+	assert.True(t, ValidTrendingPeriod("7d"))
+	assert.True(t, ValidTrendingPeriod("30d"))
+	assert.False(t, ValidTrendingPeriod("1y"))
+	assert.False(t, ValidTrendingPeriod(""))
+}
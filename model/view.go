@@ -0,0 +1,206 @@
+package model
+
+import (
+	"blog-backend/logger"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// defaultBotUserAgents - User-Agentにこれらの文字列を含むアクセスは閲覧数に数えない
+var defaultBotUserAgents = []string{
+	"bot", "crawler", "spider", "slurp", "crawl", "preview", "facebookexternalhit",
+	"curl", "wget", "python-requests", "go-http-client", "httpclient", "okhttp",
+	"headless", "lighthouse", "pingdom", "uptime", "monitor", "health",
+}
+
+// ArticleView - 記事へのアクセス1回分
+type ArticleView struct {
+	ArticleID   uuid.UUID
+	VisitorHash string
+	IsBot       bool
+	ViewedAt    time.Time
+}
+
+// ViewDedupWindow - 同じ訪問者の閲覧を1回として数える期間
+func ViewDedupWindow() time.Duration {
+	return time.Duration(getEnvInt("VIEW_DEDUP_WINDOW_MINUTES", 30)) * time.Minute
+}
+
+// IsBotUserAgent - User-Agentが空、またはBOT_USER_AGENTS(カンマ区切り)か既定の一覧に含まれる文字列を含む場合はtrue
+func IsBotUserAgent(userAgent string) bool {
+	ua := strings.ToLower(strings.TrimSpace(userAgent))
+	if ua == "" {
+		return true
+	}
+	patterns := defaultBotUserAgents
+	if extra := getEnv("BOT_USER_AGENTS", ""); extra != "" {
+		patterns = append(append([]string{}, patterns...), strings.Split(strings.ToLower(extra), ",")...)
+	}
+	for _, pattern := range patterns {
+		if pattern = strings.TrimSpace(pattern); pattern != "" && strings.Contains(ua, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// VisitorHash - IPアドレスとUser-Agentから訪問者を識別するハッシュ
+func VisitorHash(ipAddress string, userAgent string) string {
+	sum := sha256.Sum256([]byte(ipAddress + "\n" + userAgent))
+	return hex.EncodeToString(sum[:])
+}
+
+// RecordArticleViews - まとめて受け取ったアクセスを記録する
+// アクセス数は記事ごとに合計して1回のUPDATEで増やす。閲覧数はボットを除き、期間内に数えていない訪問者の分だけ増やす
+// 書き込むまでの間に完全に削除された記事へのアクセスは捨てる
+func (repo *Repository) RecordArticleViews(ctx context.Context, views []ArticleView) error {
+	if len(views) == 0 {
+		return nil
+	}
+	views, err := repo.viewsOfExistingArticles(ctx, views)
+	if err != nil || len(views) == 0 {
+		return err
+	}
+	type visitor struct {
+		articleID   uuid.UUID
+		visitorHash string
+	}
+	type viewHour struct {
+		articleID uuid.UUID
+		hour      time.Time
+	}
+	hits := make(map[uuid.UUID]int64)
+	uniqueViews := make(map[uuid.UUID]int64)
+	// トレンドの計算に使う、1時間ごとの閲覧数
+	hourlyViews := make(map[viewHour]int64)
+	// 同じバッチ内の同じ訪問者は最初のアクセスだけを調べる
+	visitors := make(map[visitor]time.Time)
+	visitorOrder := make([]visitor, 0)
+	for _, view := range views {
+		hits[view.ArticleID]++
+		if view.IsBot {
+			continue
+		}
+		key := visitor{articleID: view.ArticleID, visitorHash: view.VisitorHash}
+		if _, ok := visitors[key]; !ok {
+			visitors[key] = view.ViewedAt
+			visitorOrder = append(visitorOrder, key)
+		}
+	}
+
+	tx, err := repo.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	for _, key := range visitorOrder {
+		viewedAt := visitors[key]
+		// 新しく挿入した場合は1、期間を過ぎていてcounted_atを更新した場合は2、期間内で変更がない場合は0行になる
+		result, err := tx.ExecContext(ctx, "INSERT INTO article_views (article_id, visitor_hash, counted_at) VALUES (?, ?, ?) ON DUPLICATE KEY UPDATE counted_at = IF(counted_at < ?, VALUES(counted_at), counted_at)",
+			key.articleID, key.visitorHash, viewedAt, viewedAt.Add(-ViewDedupWindow()))
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+		rows, err := result.RowsAffected()
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+		if rows > 0 {
+			uniqueViews[key.articleID]++
+			hourlyViews[viewHour{articleID: key.articleID, hour: viewedAt.Truncate(time.Hour)}]++
+		}
+	}
+	for key, count := range hourlyViews {
+		_, err = tx.ExecContext(ctx, "INSERT INTO article_view_hours (article_id, hour, views) VALUES (?, ?, ?) ON DUPLICATE KEY UPDATE views = views + VALUES(views)", key.articleID, key.hour, count)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	for articleID, hitCount := range hits {
+		_, err = tx.ExecContext(ctx, "UPDATE articles SET hit_count = hit_count + ?, view_count = view_count + ? WHERE id = ?", hitCount, uniqueViews[articleID], articleID)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	for articleID, count := range uniqueViews {
+		repo.addCachedViewCount(articleID, count)
+	}
+	return nil
+}
+
+// viewsOfExistingArticles - ゴミ箱にあるものも含め、まだ存在する記事へのアクセスだけを返す
+func (repo *Repository) viewsOfExistingArticles(ctx context.Context, views []ArticleView) ([]ArticleView, error) {
+	ids := make([]uuid.UUID, 0)
+	seen := make(map[uuid.UUID]bool)
+	for _, view := range views {
+		if !seen[view.ArticleID] {
+			seen[view.ArticleID] = true
+			ids = append(ids, view.ArticleID)
+		}
+	}
+	query, args, err := sqlx.In("SELECT id FROM articles WHERE id IN (?)", ids)
+	if err != nil {
+		return nil, err
+	}
+	var found []uuid.UUID
+	if err := repo.db.SelectContext(ctx, &found, repo.db.Rebind(query), args...); err != nil {
+		return nil, err
+	}
+	exists := make(map[uuid.UUID]bool)
+	for _, id := range found {
+		exists[id] = true
+	}
+	kept := make([]ArticleView, 0, len(views))
+	for _, view := range views {
+		if exists[view.ArticleID] {
+			kept = append(kept, view)
+		}
+	}
+	if dropped := len(views) - len(kept); dropped > 0 {
+		logger.Printf("Dropped %d views of deleted articles", dropped)
+	}
+	return kept, nil
+}
+
+// DeleteExpiredArticleViews - 重複排除の期間を過ぎた訪問者の記録を削除する
+func (repo *Repository) DeleteExpiredArticleViews(ctx context.Context, before time.Time) error {
+	_, err := repo.db.ExecContext(ctx, "DELETE FROM article_views WHERE counted_at < ?", before)
+	return err
+}
+
+// addCachedViewCount - 閲覧のたびにキャッシュを捨てないよう、キャッシュ側の閲覧数も増やす
+func (repo *Repository) addCachedViewCount(id uuid.UUID, delta int64) {
+	repo.cache.Update(cacheKeyArticle(id.String()), func(value interface{}) interface{} {
+		article := value.(Article)
+		article.ViewCount.Int64 += delta
+		article.ViewCount.Valid = true
+		return article
+	})
+}
+
+// StartArticleViewCleaner - 期間を過ぎた訪問者の記録を定期的に削除する
+func StartArticleViewCleaner(repo *Repository, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			err := repo.DeleteExpiredArticleViews(context.Background(), time.Now().Add(-ViewDedupWindow()))
+			if err != nil {
+				logger.Println("DeleteExpiredArticleViews Error: ", err)
+			}
+			<-ticker.C
+		}
+	}()
+}
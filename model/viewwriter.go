@@ -0,0 +1,160 @@
+package model
+
+import (
+	"blog-backend/logger"
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// ViewWriter - 記事のアクセスとanalysisの記録をメモリに溜め、一定間隔でまとめてMySQLに書き込む
+// 読み込みのたびにarticlesの行をUPDATEすると編集とロックを取り合うため
+type ViewWriter struct {
+	repo     *Repository
+	interval time.Duration
+	maxBatch int
+
+	mu       sync.Mutex
+	views    []ArticleView
+	analyses []Analysis
+
+	kick chan struct{} // maxBatchを超えたときに次の間隔を待たずに書き込む
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewViewWriter - VIEW_FLUSH_INTERVAL_SECONDS(既定5秒)ごとに書き込む。Closeで残りを書き込んで止まる
+func NewViewWriter(repo *Repository) *ViewWriter {
+	w := &ViewWriter{
+		repo:     repo,
+		interval: time.Duration(getEnvInt("VIEW_FLUSH_INTERVAL_SECONDS", 5)) * time.Second,
+		maxBatch: getEnvInt("VIEW_FLUSH_MAX_BATCH", 1000),
+		kick:     make(chan struct{}, 1),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	go w.run()
+	return w
+}
+
+// EnqueueView - アクセスを書き込み待ちに追加する。リクエストを待たせないようにすぐ戻る
+func (w *ViewWriter) EnqueueView(view ArticleView) {
+	w.mu.Lock()
+	w.views = append(w.views, view)
+	full := len(w.views)+len(w.analyses) >= w.maxBatch
+	w.mu.Unlock()
+	if full {
+		w.signal()
+	}
+}
+
+// EnqueueAnalysis - analysisの記録を書き込み待ちに追加する
+func (w *ViewWriter) EnqueueAnalysis(analysis Analysis) {
+	w.mu.Lock()
+	w.analyses = append(w.analyses, analysis)
+	full := len(w.views)+len(w.analyses) >= w.maxBatch
+	w.mu.Unlock()
+	if full {
+		w.signal()
+	}
+}
+
+func (w *ViewWriter) signal() {
+	select {
+	case w.kick <- struct{}{}:
+	default:
+	}
+}
+
+func (w *ViewWriter) run() {
+	defer close(w.done)
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			w.Flush(context.Background())
+		case <-w.kick:
+			w.Flush(context.Background())
+		case <-w.stop:
+			w.Flush(context.Background())
+			return
+		}
+	}
+}
+
+// writeChunkSize - 1回の書き込みにまとめる記録の数
+// analysisは1行で7個のプレースホルダーを使うため、MySQLの上限(65,535)を十分に下回るようにする
+const writeChunkSize = 500
+
+// Flush - 溜まっている記録をwriteChunkSizeずつ書き込む。失敗した場合は次回に再び書き込めるよう、その分だけ戻しておく
+// MySQLが行を拒否した場合は何度書き込んでも失敗するので、戻さずに捨てる
+func (w *ViewWriter) Flush(ctx context.Context) {
+	w.mu.Lock()
+	views, analyses := w.views, w.analyses
+	w.views, w.analyses = nil, nil
+	w.mu.Unlock()
+	if len(views) == 0 && len(analyses) == 0 {
+		return
+	}
+
+	for chunk := range slices.Chunk(views, writeChunkSize) {
+		if err := w.repo.RecordArticleViews(ctx, chunk); err != nil {
+			logger.Println("RecordArticleViews Error: ", err)
+			if isRetryableWriteError(err) {
+				w.requeue(chunk, nil)
+			}
+		}
+	}
+	for chunk := range slices.Chunk(analyses, writeChunkSize) {
+		if err := w.repo.CreateAnalyses(ctx, chunk); err != nil {
+			logger.Println("CreateAnalyses Error: ", err)
+			if isRetryableWriteError(err) {
+				w.requeue(nil, chunk)
+				continue
+			}
+			// 拒否された行だけを捨てるため、1行ずつ書き込み直す
+			for _, analysis := range chunk {
+				if err := w.repo.CreateAnalysis(ctx, analysis); err != nil {
+					logger.Println("CreateAnalysis Error: ", err)
+					if isRetryableWriteError(err) {
+						w.requeue(nil, []Analysis{analysis})
+					}
+				}
+			}
+		}
+	}
+}
+
+// isRetryableWriteError - 接続の切断やロック待ちのように、書き込み直せば成功しうるエラーかを調べる
+func isRetryableWriteError(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	if !errors.As(err, &mysqlErr) {
+		return true
+	}
+	// 1205: ロック待ちのタイムアウト、1213: デッドロック
+	return mysqlErr.Number == 1205 || mysqlErr.Number == 1213
+}
+
+// requeue - 書き込めなかった記録を戻す。DBが止まり続けてもメモリを使い切らないよう、maxBatchの10倍を超えた分は捨てる
+func (w *ViewWriter) requeue(views []ArticleView, analyses []Analysis) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	limit := w.maxBatch * 10
+	if len(views) > 0 && len(w.views)+len(views) <= limit {
+		w.views = append(views, w.views...)
+	}
+	if len(analyses) > 0 && len(w.analyses)+len(analyses) <= limit {
+		w.analyses = append(analyses, w.analyses...)
+	}
+}
+
+// Close - 書き込み待ちの記録をすべて書き込んでから止める。graceful shutdownのときに呼ぶ
+func (w *ViewWriter) Close() {
+	close(w.stop)
+	<-w.done
+}